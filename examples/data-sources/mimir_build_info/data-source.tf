@@ -0,0 +1,5 @@
+data "mimir_build_info" "cluster" {}
+
+output "mimir_version" {
+  value = data.mimir_build_info.cluster.version
+}
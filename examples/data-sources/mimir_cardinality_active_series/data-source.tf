@@ -0,0 +1,4 @@
+data "mimir_cardinality_active_series" "api" {
+  org_id   = "team-a"
+  selector = "{job=\"api\"}"
+}
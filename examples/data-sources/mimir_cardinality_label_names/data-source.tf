@@ -0,0 +1,5 @@
+data "mimir_cardinality_label_names" "tenant" {
+  org_id       = "team-a"
+  limit        = 10
+  count_method = "active"
+}
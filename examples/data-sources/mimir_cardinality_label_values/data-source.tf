@@ -0,0 +1,6 @@
+# Top metric names of a tenant
+data "mimir_cardinality_label_values" "metric_names" {
+  org_id      = "team-a"
+  label_names = ["__name__"]
+  limit       = 10
+}
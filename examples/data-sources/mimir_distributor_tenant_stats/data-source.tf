@@ -1 +1,9 @@
 data "mimir_distributor_tenant_stats" "tenants" {}
+
+# The 10 tenants with the most in-memory series.
+data "mimir_distributor_tenant_stats" "top_series" {
+  user_regex         = "team-.*"
+  sort_by            = "series"
+  top                = 10
+  replication_factor = 3
+}
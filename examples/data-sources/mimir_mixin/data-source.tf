@@ -0,0 +1,14 @@
+# Mixins vendored with jsonnet-bundler: jb install github.com/grafana/mimir/operations/mimir-mixin@main
+data "mimir_mixin" "mimir" {
+  path  = "${path.module}/vendor/mimir-mixin/mixin.libsonnet"
+  jpath = ["${path.module}/vendor"]
+
+  config = jsonencode({
+    product = "Mimir"
+  })
+}
+
+resource "mimir_rules" "mimir_mixin" {
+  namespace = "mimir-mixin"
+  content   = data.mimir_mixin.mimir.content
+}
@@ -0,0 +1,13 @@
+# Instant query
+data "mimir_query" "series" {
+  query = "sum(cortex_ingester_memory_series)"
+}
+
+# Range query, in another tenant
+data "mimir_query" "recording_rule" {
+  org_id = "team-a"
+  query  = "job:http_requests:rate5m"
+  start  = "2024-01-01T00:00:00Z"
+  end    = "2024-01-01T01:00:00Z"
+  step   = "5m"
+}
@@ -0,0 +1,15 @@
+data "mimir_ring_status" "ruler" {
+  ring = "ruler"
+}
+
+resource "mimir_rules" "team_a" {
+  namespace = "team-a"
+  content   = file("${path.module}/rules/team-a.yaml")
+
+  lifecycle {
+    precondition {
+      condition     = data.mimir_ring_status.ruler.healthy
+      error_message = "The ruler ring is not healthy."
+    }
+  }
+}
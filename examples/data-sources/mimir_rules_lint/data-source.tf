@@ -0,0 +1,12 @@
+data "mimir_rules_lint" "team_a" {
+  namespace = "team-a"
+  content   = file("${path.module}/rules/team-a.yaml")
+
+  policy {
+    required_alert_labels = ["severity"]
+  }
+}
+
+output "rule_lint_findings" {
+  value = data.mimir_rules_lint.team_a.findings
+}
@@ -0,0 +1,7 @@
+data "mimir_services_status" "alertmanager" {
+  component = "alertmanager"
+}
+
+output "alertmanager_services_not_running" {
+  value = data.mimir_services_status.alertmanager.not_running
+}
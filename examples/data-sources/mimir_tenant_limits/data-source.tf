@@ -0,0 +1,7 @@
+data "mimir_tenant_limits" "team_a" {
+  org_id = "team-a"
+}
+
+output "team_a_max_series" {
+  value = data.mimir_tenant_limits.team_a.max_global_series_per_user
+}
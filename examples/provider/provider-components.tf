@@ -0,0 +1,21 @@
+provider "mimir" {
+  uri    = "https://mimir.example.com"
+  org_id = "mytenant"
+  token  = var.mimir_token
+
+  ruler {
+    uri   = "https://ruler.example.com/prometheus"
+    ca    = "/etc/ssl/ruler-ca.pem"
+    token = var.mimir_ruler_token
+  }
+
+  alertmanager {
+    uri      = "https://alertmanager.example.com"
+    username = "alerting"
+    password = var.mimir_alertmanager_password
+    timeout  = 10
+    headers = {
+      "X-Team" = "alerting"
+    }
+  }
+}
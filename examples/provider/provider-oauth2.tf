@@ -0,0 +1,16 @@
+provider "mimir" {
+  ruler_uri = "http://127.0.0.1:8080/prometheus"
+  alertmanager_uri = "http://127.0.0.1:8080"
+  distributor_uri = "http://127.0.0.1:8080/distributor"
+  org_id = "mytenant"
+
+  oauth2 {
+    token_url     = "https://auth.example.com/oauth2/token"
+    client_id     = "terraform"
+    client_secret = var.mimir_client_secret
+    scopes        = ["mimir"]
+    endpoint_params = {
+      audience = "mimir"
+    }
+  }
+}
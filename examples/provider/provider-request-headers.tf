@@ -0,0 +1,23 @@
+provider "mimir" {
+  uri    = "https://mimir-gateway.example.com"
+  org_id = "mytenant"
+}
+
+resource "mimir_rule_group_alerting" "team_a" {
+  org_id    = "team-a"
+  name      = "team_a_alerts"
+  namespace = "team-a"
+
+  request_headers = {
+    "X-Gateway-Route" = "team-a"
+  }
+
+  auth {
+    token = var.team_a_token
+  }
+
+  rule {
+    alert = "HighErrorRate"
+    expr  = "sum(rate(http_requests_total{status=~\"5..\"}[5m])) > 1"
+  }
+}
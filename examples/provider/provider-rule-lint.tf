@@ -0,0 +1,27 @@
+provider "mimir" {
+  ruler_uri = "http://127.0.0.1:8080/prometheus"
+  alertmanager_uri = "http://127.0.0.1:8080"
+  distributor_uri = "http://127.0.0.1:8080/distributor"
+  org_id = "mytenant"
+
+  rule_lint {
+    name                       = "alerts"
+    required_alert_labels      = ["severity"]
+    required_alert_annotations = ["runbook_url"]
+  }
+
+  rule_lint {
+    name                       = "recording-names"
+    severity                   = "warning"
+    namespaces                 = ["team-.*"]
+    recording_rule_name_format = true
+  }
+
+  rule_lint {
+    name                   = "paging"
+    min_for                = "5m"
+    min_for_label_selector = {
+      severity = "page|critical"
+    }
+  }
+}
@@ -0,0 +1,11 @@
+provider "mimir" {
+  ruler_uri = "https://abcdef1234.execute-api.eu-west-1.amazonaws.com/prometheus"
+  alertmanager_uri = "https://abcdef1234.execute-api.eu-west-1.amazonaws.com"
+  org_id = "mytenant"
+
+  sigv4 {
+    region   = "eu-west-1"
+    role_arn = "arn:aws:iam::123456789012:role/mimir-terraform"
+    service  = "execute-api"
+  }
+}
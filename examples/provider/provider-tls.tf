@@ -0,0 +1,9 @@
+provider "mimir" {
+  uri    = "https://mimir.example.com"
+  org_id = "mytenant"
+
+  cert              = "/var/run/secrets/mimir-client/tls.crt"
+  key               = "/var/run/secrets/mimir-client/tls.key"
+  ca                = "/var/run/secrets/mimir-ca"
+  include_system_ca = true
+}
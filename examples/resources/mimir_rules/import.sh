@@ -0,0 +1,5 @@
+# Import every rule group of a namespace
+terraform import mimir_rules.test {{org_id/namespace}}
+
+# Import only some rule groups of a namespace
+terraform import mimir_rules.test '{{org_id/namespace}}?groups={{group1,group2}}'
@@ -0,0 +1,29 @@
+# Every rule group of a single file
+resource "mimir_rules" "kubernetes" {
+  namespace    = "kubernetes"
+  content_file = "${path.module}/rules/kubernetes.yaml"
+}
+
+# The rule groups of the files generated by a mixin, merged into one namespace
+resource "mimir_rules" "mimir_mixin" {
+  namespace = "mimir-mixin"
+  content_files = [
+    "${path.module}/mimir-mixin/alerts.yaml",
+    "${path.module}/mimir-mixin/rules.yaml",
+  ]
+  parallelism = 8
+}
+
+# One file per service, e.g. rules/services/api.yaml and rules/services/billing.yml
+resource "mimir_rules" "services" {
+  namespace   = "services"
+  content_dir = "${path.module}/rules/services"
+}
+
+# The PrometheusRule manifests of a Kubernetes namespace, shared with the Prometheus Operator
+resource "mimir_rules" "team_a" {
+  namespace      = "team-a"
+  content_file   = "${path.module}/manifests/prometheusrules.yaml"
+  content_format = "prometheusrule"
+  namespace_from = "metadata.namespace"
+}
@@ -0,0 +1,6 @@
+resource "mimir_series_deletion_request" "leaked_emails" {
+  org_id   = "team-a"
+  matchers = ["{__name__=\"http_requests_total\", user_email!=\"\"}"]
+  start    = "2024-03-01T00:00:00Z"
+  end      = "2024-03-08T00:00:00Z"
+}
@@ -0,0 +1,8 @@
+resource "mimir_tenant_deletion" "team_old" {
+  org_id         = "team-old"
+  confirm_org_id = "team-old"
+
+  timeouts {
+    create = "2h"
+  }
+}
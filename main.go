@@ -2,6 +2,8 @@ package main
 
 import (
 	"flag"
+	"fmt"
+	"os"
 
 	"github.com/fgouteroux/terraform-provider-mimir/mimir"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
@@ -14,6 +16,16 @@ var (
 )
 
 func main() {
+	// `terraform-provider-mimir generate ...` exports an existing tenant as Terraform config
+	// instead of serving the provider plugin.
+	if len(os.Args) > 1 && os.Args[1] == "generate" {
+		if err := mimir.RunGenerate(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	var debugMode bool
 
 	flag.BoolVar(&debugMode, "debug", false, "set to true to run the provider with support for debuggers like delve")
@@ -4,49 +4,92 @@ package mimir
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
-	"net/http/httputil"
 	"net/url"
-	"os"
 	"strings"
 	"time"
+
+	"github.com/hashicorp/go-version"
+	"github.com/prometheus/sigv4"
+	"golang.org/x/oauth2/clientcredentials"
 )
 
 type apiClientOpt struct {
-	uri             string
-	rulerURI        string
-	alertmanagerURI string
-	distributorURI  string
-	cert            string
-	key             string
-	ca              string
-	token           string
-	insecure        bool
-	username        string
-	password        string
-	proxyURL        string
-	headers         map[string]string
-	timeout         int
-	debug           bool
+	uri              string
+	rulerURI         string
+	alertmanagerURI  string
+	distributorURI   string
+	queryFrontendURI string
+	ingesterURI      string
+	storeGatewayURI  string
+	compactorURI     string
+	cert             string
+	key              string
+	ca               string
+	includeSystemCA  bool
+	token            string
+	tokenFile        string
+	oauth2           *clientcredentials.Config
+	sigv4            *sigv4.SigV4Config
+	insecure         bool
+	username         string
+	password         string
+	proxyURL         string
+	headers          map[string]string
+	timeout          int
+	debug            bool
+	// components holds the options of the component blocks, by component name. Only uri,
+	// TLS, auth, headers and timeout are used.
+	components map[string]*apiClientOpt
+}
+
+// credentials are the authentication settings applied to a request.
+type credentials struct {
+	token       string
+	tokenFile   string
+	tokenSource *oauth2TokenSource
+	username    string
+	password    string
+}
+
+// componentClient holds the settings of a component block, used instead of the
+// provider-level ones for the requests sent to that component.
+type componentClient struct {
+	credentials
+	// uri is empty when the block does not set it.
+	uri        string
+	httpClient *http.Client
+	headers    map[string]string
 }
 
 type apiClient struct {
-	httpClient      *http.Client
-	uri             string
-	rulerURI        string
-	alertmanagerURI string
-	distributorURI  string
-	insecure        bool
-	token           string
-	username        string
-	password        string
-	headers         map[string]string
-	debug           bool
+	credentials
+	httpClient       *http.Client
+	uri              string
+	rulerURI         string
+	alertmanagerURI  string
+	distributorURI   string
+	queryFrontendURI string
+	ingesterURI      string
+	storeGatewayURI  string
+	compactorURI     string
+	insecure         bool
+	headers          map[string]string
+	debug            bool
+	components       map[string]*componentClient
+	// requestHeaders and requestAuth are the request_headers and auth of a resource, see forResource.
+	requestHeaders map[string]string
+	requestAuth    *credentials
+	// version is the Mimir version detected at configure time, nil if unknown.
+	version *version.Version
+	// rulesParallelism is the provider default of the mimir_rules parallelism.
+	rulesParallelism int
 }
 
 // Make a new api client for RESTful calls
@@ -55,6 +98,53 @@ func NewAPIClient(opt *apiClientOpt) (*apiClient, error) {
 	   to this URL with our own root-prefixed location */
 	opt.uri = strings.TrimSuffix(opt.uri, "/")
 
+	httpClient, creds, err := newHTTPClient(opt)
+	if err != nil {
+		return nil, err
+	}
+
+	client := apiClient{
+		credentials:      creds,
+		httpClient:       httpClient,
+		uri:              opt.uri,
+		rulerURI:         opt.rulerURI,
+		alertmanagerURI:  opt.alertmanagerURI,
+		distributorURI:   opt.distributorURI,
+		queryFrontendURI: opt.queryFrontendURI,
+		ingesterURI:      opt.ingesterURI,
+		storeGatewayURI:  opt.storeGatewayURI,
+		compactorURI:     opt.compactorURI,
+		insecure:         opt.insecure,
+		headers:          opt.headers,
+		debug:            opt.debug,
+		components:       make(map[string]*componentClient, len(opt.components)),
+	}
+
+	for name, componentOpt := range opt.components {
+		httpClient, creds, err := newHTTPClient(componentOpt)
+		if err != nil {
+			return nil, fmt.Errorf("%s block: %v", name, err)
+		}
+		client.components[name] = &componentClient{
+			credentials: creds,
+			uri:         strings.TrimSuffix(componentOpt.uri, "/"),
+			httpClient:  httpClient,
+			headers:     componentOpt.headers,
+		}
+	}
+
+	return &client, nil
+}
+
+// newHTTPClient builds the HTTP client and the credentials of a set of options.
+func newHTTPClient(opt *apiClientOpt) (*http.Client, credentials, error) {
+	creds := credentials{
+		token:     opt.token,
+		tokenFile: opt.tokenFile,
+		username:  opt.username,
+		password:  opt.password,
+	}
+
 	// Setup HTTPS client
 	tlsConfig := &tls.Config{}
 
@@ -64,68 +154,114 @@ func NewAPIClient(opt *apiClientOpt) (*apiClient, error) {
 	}
 
 	if opt.cert != "" && opt.key != "" {
-		var cert tls.Certificate
-		var err error
-		if strings.HasPrefix(opt.cert, "-----BEGIN") && strings.HasPrefix(opt.key, "-----BEGIN") {
-			cert, err = tls.X509KeyPair([]byte(opt.cert), []byte(opt.key))
-		} else {
-			cert, err = tls.LoadX509KeyPair(opt.cert, opt.key)
-		}
+		certs, err := newCertReloader(opt.cert, opt.key, opt.debug)
 		if err != nil {
-			return nil, err
+			return nil, creds, err
 		}
-		tlsConfig.Certificates = []tls.Certificate{cert}
+		tlsConfig.GetClientCertificate = certs.GetClientCertificate
 	}
 
+	var ca *caReloader
 	if opt.ca != "" {
-		var caCert []byte
 		var err error
-		if strings.HasPrefix(opt.ca, "-----BEGIN") {
-			caCert = []byte(opt.ca)
-		} else {
-			caCert, err = os.ReadFile(opt.ca)
-
-			if err != nil {
-				return nil, err
-			}
+		ca, err = newCAReloader(opt.ca, opt.includeSystemCA)
+		if err != nil {
+			return nil, creds, err
 		}
-		caCertPool := x509.NewCertPool()
-		caCertPool.AppendCertsFromPEM(caCert)
-		tlsConfig.RootCAs = caCertPool
-	}
-
-	tr := &http.Transport{
-		TLSClientConfig: tlsConfig,
-		Proxy:           http.ProxyFromEnvironment,
 	}
 
+	var proxy func(*http.Request) (*url.URL, error) = http.ProxyFromEnvironment
 	if opt.proxyURL != "" {
 		log.Printf("api_client.go: Using proxy: %s\n", opt.proxyURL)
-		proxy, err := url.Parse(opt.proxyURL)
+		proxyURL, err := url.Parse(opt.proxyURL)
 		if err != nil {
-			return nil, fmt.Errorf("error parsing proxy url: %s", err)
+			return nil, creds, fmt.Errorf("error parsing proxy url: %s", err)
 		}
-		tr.Proxy = http.ProxyURL(proxy)
+		proxy = http.ProxyURL(proxyURL)
 	}
 
-	client := apiClient{
-		httpClient: &http.Client{
+	newTransport := func(rootCAs *x509.CertPool) *http.Transport {
+		config := tlsConfig.Clone()
+		config.RootCAs = rootCAs
+		return &http.Transport{
+			TLSClientConfig: config,
+			Proxy:           proxy,
+		}
+	}
+
+	var tr http.RoundTripper
+	if ca != nil && ca.reloadable() {
+		tr = newCATransport(ca, newTransport)
+	} else if ca != nil {
+		pool, _, _ := ca.load()
+		tr = newTransport(pool)
+	} else {
+		tr = newTransport(nil)
+	}
+
+	transport := tr
+	if opt.sigv4 != nil {
+		rt, err := sigv4.NewSigV4RoundTripper(opt.sigv4, tr)
+		if err != nil {
+			return nil, creds, fmt.Errorf("error configuring sigv4: %s", err)
+		}
+		transport = rt
+	}
+
+	if opt.oauth2 != nil {
+		// The token endpoint is reached with the same TLS and proxy settings, but unsigned.
+		creds.tokenSource = newOAuth2TokenSource(opt.oauth2, &http.Client{
 			Timeout:   time.Second * time.Duration(opt.timeout),
 			Transport: tr,
-		},
-		uri:             opt.uri,
-		rulerURI:        opt.rulerURI,
-		alertmanagerURI: opt.alertmanagerURI,
-		distributorURI:  opt.distributorURI,
-		insecure:        opt.insecure,
-		token:           opt.token,
-		username:        opt.username,
-		password:        opt.password,
-		headers:         opt.headers,
-		debug:           opt.debug,
+		})
 	}
 
-	return &client, nil
+	return &http.Client{
+		Timeout:   time.Second * time.Duration(opt.timeout),
+		Transport: transport,
+	}, creds, nil
+}
+
+// componentPrefixes are the path prefixes documented for the <component>_uri settings,
+// e.g. `ruler_uri = "http://mimir/prometheus"`, stripped to reach the endpoints served at
+// the root of the component, like its ring or services pages.
+var componentPrefixes = map[string]string{
+	"ruler":          "/prometheus",
+	"distributor":    "/distributor",
+	"query_frontend": "/prometheus",
+}
+
+// componentURI returns the base url of component: the uri of its block, its
+// <component>_uri, then uri. With root, the documented prefix of the component url is
+// stripped.
+func (client *apiClient) componentURI(component string, root bool) string {
+	var uri string
+	switch component {
+	case "ruler":
+		uri = client.rulerURI
+	case "alertmanager":
+		uri = client.alertmanagerURI
+	case "distributor":
+		uri = client.distributorURI
+	case "query_frontend":
+		uri = client.queryFrontendURI
+	case "ingester":
+		uri = client.ingesterURI
+	case "store_gateway":
+		uri = client.storeGatewayURI
+	case "compactor":
+		uri = client.compactorURI
+	}
+	if c, ok := client.components[component]; ok && c.uri != "" {
+		uri = c.uri
+	}
+	if uri == "" {
+		return client.uri
+	}
+	if root {
+		uri = strings.TrimSuffix(strings.TrimSuffix(uri, "/"), componentPrefixes[component])
+	}
+	return uri
 }
 
 /*
@@ -133,18 +269,26 @@ Helper function that handles sending/receiving and handling
 
 	of HTTP data in and out.
 */
-func (client *apiClient) sendRequest(component, method string, path, data string, headers map[string]string) (string, error) {
-	var fullURI string
+func (client *apiClient) sendRequest(ctx context.Context, component, method string, path, data string, headers map[string]string) (string, error) {
+	return client.sendRequestURI(ctx, component, method, client.componentURI(component, false), path, data, headers)
+}
+
+// sendRootRequest is sendRequest for the endpoints served at the root of the component,
+// whatever the prefix of its url.
+func (client *apiClient) sendRootRequest(ctx context.Context, component, method string, path, data string, headers map[string]string) (string, error) {
+	return client.sendRequestURI(ctx, component, method, client.componentURI(component, true), path, data, headers)
+}
+
+func (client *apiClient) sendRequestURI(ctx context.Context, component, method, baseURI, path, data string, headers map[string]string) (string, error) {
+	fullURI := baseURI + path
 
-	switch {
-	case component == "ruler" && client.rulerURI != "":
-		fullURI = client.rulerURI + path
-	case component == "alertmanager" && client.alertmanagerURI != "":
-		fullURI = client.alertmanagerURI + path
-	case component == "distributor" && client.distributorURI != "":
-		fullURI = client.distributorURI + path
-	default:
-		fullURI = client.uri + path
+	httpClient, creds := client.httpClient, client.credentials
+	var componentHeaders map[string]string
+	if c, ok := client.components[component]; ok {
+		httpClient, creds, componentHeaders = c.httpClient, c.credentials, c.headers
+	}
+	if client.requestAuth != nil {
+		creds = *client.requestAuth
 	}
 
 	var req *http.Request
@@ -153,17 +297,17 @@ func (client *apiClient) sendRequest(component, method string, path, data string
 	buffer := bytes.NewBuffer([]byte(data))
 
 	if data == "" {
-		req, err = http.NewRequest(method, fullURI, nil)
+		req, err = http.NewRequestWithContext(ctx, method, fullURI, nil)
 	} else {
-		req, err = http.NewRequest(method, fullURI, buffer)
+		req, err = http.NewRequestWithContext(ctx, method, fullURI, buffer)
 	}
 
 	if err != nil {
-		log.Fatal(err)
+		return "", err
 	}
 
-	if client.token != "" {
-		req.Header.Set("Authorization", "Bearer "+client.token)
+	if err := creds.setAuthorization(req); err != nil {
+		return "", err
 	}
 
 	// Set client headers from provider
@@ -173,52 +317,47 @@ func (client *apiClient) sendRequest(component, method string, path, data string
 		}
 	}
 
-	// Set client headers from resource
-	if len(headers) > 0 {
-		for n, v := range headers {
-			req.Header.Set(n, v)
-		}
+	// Set client headers from the component block
+	for n, v := range componentHeaders {
+		req.Header.Set(n, v)
 	}
 
-	if client.username != "" && client.password != "" {
+	if creds.username != "" && creds.password != "" {
 		/* ... and fall back to basic auth if configured */
-		req.SetBasicAuth(client.username, client.password)
+		req.SetBasicAuth(creds.username, creds.password)
 	}
 
-	if client.debug {
-		reqDump, err := httputil.DumpRequestOut(req, true)
-		if err != nil {
-			log.Fatal(err)
-		}
-
-		log.Printf("REQUEST:\n%s", string(reqDump))
+	// Set client headers from the resource request_headers, which may override the authorization
+	for n, v := range client.requestHeaders {
+		req.Header.Set(n, v)
 	}
 
-	resp, err := client.httpClient.Do(req)
-
-	if err != nil {
-		if client.debug {
-			log.Printf("api_client.go: Error detected: %s\n", err)
+	// Set client headers from resource
+	if len(headers) > 0 {
+		for n, v := range headers {
+			req.Header.Set(n, v)
 		}
-		return "", err
 	}
 
-	if client.debug {
-		respDump, err := httputil.DumpResponse(resp, true)
-		if err != nil {
-			log.Fatal(err)
-		}
+	ctx = requestLogContext(ctx, component, method, path, req.Header.Get("X-Scope-OrgID"), client.headers, componentHeaders, client.requestHeaders)
+	logRequest(ctx, req, data, client.debug)
 
-		log.Printf("RESPONSE:\n%s", string(respDump))
+	start := time.Now()
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		logRequestError(ctx, err, time.Since(start))
+		return "", err
 	}
 
 	bodyBytes, err2 := io.ReadAll(resp.Body)
 	resp.Body.Close()
 
 	if err2 != nil {
+		logRequestError(ctx, err2, time.Since(start))
 		return "", err2
 	}
 	body := string(bodyBytes)
+	logResponse(ctx, resp, body, time.Since(start), client.debug)
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		return body, fmt.Errorf("unexpected response code '%d': %s", resp.StatusCode, body)
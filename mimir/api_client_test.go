@@ -3,9 +3,12 @@ package mimir
 // Largely copied from https://github.com/Mastercard/terraform-provider-restapi/blob/master/restapi/api_client_test.go
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 )
@@ -40,7 +43,7 @@ func TestAPIClient(t *testing.T) {
 		log.Printf("api_client_test.go: Testing standard OK request\n")
 	}
 	var headers map[string]string
-	res, err = client.sendRequest("", "GET", "/ok", "", headers)
+	res, err = client.sendRequest(context.Background(), "", "GET", "/ok", "", headers)
 	if err != nil {
 		t.Fatalf("api_client_test.go: %s", err)
 	}
@@ -51,7 +54,7 @@ func TestAPIClient(t *testing.T) {
 	if debug {
 		log.Printf("api_client_test.go: Testing redirect request\n")
 	}
-	res, err = client.sendRequest("", "GET", "/redirect", "", headers)
+	res, err = client.sendRequest(context.Background(), "", "GET", "/redirect", "", headers)
 	if err != nil {
 		t.Fatalf("api_client_test.go: %s", err)
 	}
@@ -63,7 +66,7 @@ func TestAPIClient(t *testing.T) {
 	if debug {
 		log.Printf("api_client_test.go: Testing timeout aborts requests\n")
 	}
-	_, err = client.sendRequest("", "GET", "/slow", "", headers)
+	_, err = client.sendRequest(context.Background(), "", "GET", "/slow", "", headers)
 	if err != nil {
 		if debug {
 			log.Println("api_client_test.go: slow request expected")
@@ -164,7 +167,7 @@ func TestAPIClientBasicAuth(t *testing.T) {
 		t.Fatalf("api_client_test.go: Failed to init api client, err: %v", err)
 	}
 	var headers map[string]string
-	_, err = client.sendRequest("", "GET", "/ok", "", headers)
+	_, err = client.sendRequest(context.Background(), "", "GET", "/ok", "", headers)
 	if err != nil {
 		t.Fatalf("api_client_test.go: %s", err)
 	}
@@ -189,7 +192,7 @@ func TestAPIClientBearerAuth(t *testing.T) {
 		t.Fatalf("api_client_test.go: Failed to init api client, err: %v", err)
 	}
 	var headers map[string]string
-	_, err = client.sendRequest("", "GET", "/ok", "", headers)
+	_, err = client.sendRequest(context.Background(), "", "GET", "/ok", "", headers)
 	if err != nil {
 		t.Fatalf("api_client_test.go: %s", err)
 	}
@@ -213,7 +216,7 @@ func TestAPIClientDebug(t *testing.T) {
 		t.Fatalf("api_client_test.go: Failed to init api client, err: %v", err)
 	}
 	var headers map[string]string
-	_, err = client.sendRequest("", "GET", "/ok", "", headers)
+	_, err = client.sendRequest(context.Background(), "", "GET", "/ok", "", headers)
 	if err != nil {
 		t.Fatalf("api_client_test.go: %s", err)
 	}
@@ -239,7 +242,7 @@ func TestAPIClientHeaders(t *testing.T) {
 	if err != nil {
 		t.Fatalf("api_client_test.go: Failed to init api client, err: %v", err)
 	}
-	_, err = client.sendRequest("", "GET", "/ok", "", headers)
+	_, err = client.sendRequest(context.Background(), "", "GET", "/ok", "", headers)
 	if err != nil {
 		t.Fatalf("api_client_test.go: %s", err)
 	}
@@ -279,3 +282,67 @@ func setupAPIClientServer(debug bool, address string) {
 func shutdownAPIClientServer() {
 	apiClientServer.Close()
 }
+
+func TestAPIClientContextCancel(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	client, err := NewAPIClient(&apiClientOpt{uri: srv.URL, headers: map[string]string{}, timeout: 60})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := client.sendRequest(ctx, "", "GET", "/", "", map[string]string{}); err == nil {
+		t.Fatal("expected the request to be cancelled")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("the request was not cancelled with its context, took %s", elapsed)
+	}
+}
+
+func TestSleepContext(t *testing.T) {
+	if err := sleepContext(context.Background(), time.Millisecond); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	if err := sleepContext(ctx, time.Hour); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("sleepContext ignored the cancellation, took %s", elapsed)
+	}
+}
+
+func TestComponentURI(t *testing.T) {
+	client := &apiClient{
+		uri:            "http://mimir",
+		rulerURI:       "http://mimir/prometheus/",
+		distributorURI: "http://distributor:8080/distributor",
+		components:     map[string]*componentClient{"alertmanager": {uri: "http://alertmanager/alertmanager"}},
+	}
+	cases := []struct {
+		component string
+		root      bool
+		want      string
+	}{
+		{"ruler", false, "http://mimir/prometheus/"},
+		{"ruler", true, "http://mimir"},
+		{"distributor", true, "http://distributor:8080"},
+		{"alertmanager", true, "http://alertmanager/alertmanager"},
+		{"ingester", true, "http://mimir"},
+		{"", true, "http://mimir"},
+	}
+	for _, c := range cases {
+		if got := client.componentURI(c.component, c.root); got != c.want {
+			t.Errorf("componentURI(%q, %v) = %q, want %q", c.component, c.root, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package mimir
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/prometheus/common/config"
+	"github.com/prometheus/sigv4"
+	xoauth2 "golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+func oauth2Schema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"token_url": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "URL of the token endpoint of the authorization server.",
+			},
+			"client_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "OAuth2 client ID.",
+			},
+			"client_secret": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "OAuth2 client secret.",
+			},
+			"scopes": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Scopes requested for the token.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"endpoint_params": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: "Additional parameters sent to the token endpoint, e.g. `audience`.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+// expandOAuth2 reads the oauth2 block; it returns nil when the block is absent.
+func expandOAuth2(v []interface{}) *clientcredentials.Config {
+	if len(v) == 0 || v[0] == nil {
+		return nil
+	}
+	data := v[0].(map[string]interface{})
+
+	params := url.Values{}
+	for k, v := range data["endpoint_params"].(map[string]interface{}) {
+		params.Set(k, v.(string))
+	}
+	return &clientcredentials.Config{
+		ClientID:       data["client_id"].(string),
+		ClientSecret:   data["client_secret"].(string),
+		TokenURL:       data["token_url"].(string),
+		Scopes:         expandStringArray(data["scopes"].([]interface{})),
+		EndpointParams: params,
+	}
+}
+
+func sigv4Schema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The AWS region requests are signed for.",
+			},
+			"access_key": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "AWS access key. If blank, credentials are read from the default credentials chain.",
+			},
+			"secret_key": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "AWS secret key, required with `access_key`.",
+			},
+			"profile": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Named AWS profile used to authenticate.",
+			},
+			"role_arn": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "AWS Role ARN to assume, an alternative to using AWS API keys.",
+			},
+			"service": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "aps",
+				Description: "AWS service name requests are signed for, e.g. `execute-api` for API Gateway.",
+			},
+		},
+	}
+}
+
+// expandSigV4 reads the sigv4 block; it returns nil when the block is absent.
+func expandSigV4(v []interface{}) (*sigv4.SigV4Config, error) {
+	if len(v) == 0 || v[0] == nil {
+		return nil, nil
+	}
+	data := v[0].(map[string]interface{})
+
+	cfg := &sigv4.SigV4Config{
+		Region:      data["region"].(string),
+		AccessKey:   data["access_key"].(string),
+		SecretKey:   config.Secret(data["secret_key"].(string)),
+		Profile:     data["profile"].(string),
+		RoleARN:     data["role_arn"].(string),
+		ServiceName: data["service"].(string),
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid sigv4 block: %v", err)
+	}
+	return cfg, nil
+}
+
+// setAuthorization sets the Authorization header of req. The first configured method wins:
+// oauth2, token_file, then token. Basic auth is applied afterwards in sendRequest and
+// overrides all of them, as it always did. With sigv4, the signature replaces the header.
+func (creds credentials) setAuthorization(req *http.Request) error {
+	switch {
+	case creds.tokenSource != nil:
+		token, err := creds.tokenSource.Token(req.Context())
+		if err != nil {
+			return fmt.Errorf("unable to get an oauth2 token: %v", err)
+		}
+		token.SetAuthHeader(req)
+	case creds.tokenFile != "":
+		// Read on every request, so a rotated token is picked up immediately.
+		token, err := os.ReadFile(creds.tokenFile)
+		if err != nil {
+			return fmt.Errorf("unable to read token_file: %v", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	case creds.token != "":
+		req.Header.Set("Authorization", "Bearer "+creds.token)
+	}
+	return nil
+}
+
+// oauth2TokenSource caches the oauth2 token of a client and refreshes it shortly before it
+// expires, with the context of the request needing it.
+type oauth2TokenSource struct {
+	config *clientcredentials.Config
+	// httpClient reaches the token endpoint with the same TLS and proxy settings as Mimir.
+	httpClient *http.Client
+
+	mu    sync.Mutex
+	token *xoauth2.Token
+}
+
+func newOAuth2TokenSource(config *clientcredentials.Config, httpClient *http.Client) *oauth2TokenSource {
+	return &oauth2TokenSource{config: config, httpClient: httpClient}
+}
+
+// Token returns the cached token, or fetches a new one with ctx when it has expired.
+func (s *oauth2TokenSource) Token(ctx context.Context) (*xoauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token.Valid() {
+		return s.token, nil
+	}
+	token, err := s.config.Token(context.WithValue(ctx, xoauth2.HTTPClient, s.httpClient))
+	if err != nil {
+		return nil, err
+	}
+	s.token = token
+	return token, nil
+}
@@ -0,0 +1,212 @@
+package mimir
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+func TestOAuth2ClientCredentials(t *testing.T) {
+	var issued int32
+	tokenSrv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, secret, _ := r.BasicAuth()
+		if err := r.ParseForm(); err != nil || id != "provider" || secret != "s3cr3t" ||
+			r.PostForm.Get("grant_type") != "client_credentials" || r.PostForm.Get("scope") != "mimir:read mimir:write" ||
+			r.PostForm.Get("audience") != "mimir" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		n := atomic.AddInt32(&issued, 1)
+		w.Header().Set("Content-Type", "application/json")
+		// Tokens expire immediately, so each request has to refresh it.
+		_, _ = fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":1}`, n)
+	}))
+	srv := newTestServer(t, echoHeadersHandler("Authorization"))
+
+	client := newTestClient(t, &apiClientOpt{
+		uri:     srv.URL,
+		headers: map[string]string{},
+		timeout: 2,
+		token:   "static",
+		oauth2: expandOAuth2([]interface{}{map[string]interface{}{
+			"token_url":       tokenSrv.URL,
+			"client_id":       "provider",
+			"client_secret":   "s3cr3t",
+			"scopes":          []interface{}{"mimir:read", "mimir:write"},
+			"endpoint_params": map[string]interface{}{"audience": "mimir"},
+		}}),
+	})
+
+	for i := 1; i <= 2; i++ {
+		got, err := client.sendRequest(context.Background(), "", "GET", "/", "", map[string]string{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := fmt.Sprintf("Bearer token-%d", i); got != want {
+			t.Errorf("request %d: got Authorization %q, want %q", i, got, want)
+		}
+	}
+
+	// The token is fetched with the context of the request.
+	client.tokenSource = newOAuth2TokenSource(client.tokenSource.config, client.httpClient)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	before := atomic.LoadInt32(&issued)
+	if _, err := client.sendRequest(ctx, "", "GET", "/", "", map[string]string{}); err == nil || !strings.Contains(err.Error(), "context canceled") {
+		t.Errorf("expected the canceled context to stop the token request, got %v", err)
+	}
+	if atomic.LoadInt32(&issued) != before {
+		t.Error("unexpected token request with a canceled context")
+	}
+
+	client.tokenSource = newOAuth2TokenSource(&clientcredentials.Config{ClientID: "unknown", TokenURL: tokenSrv.URL}, client.httpClient)
+	if _, err := client.sendRequest(context.Background(), "", "GET", "/", "", map[string]string{}); err == nil {
+		t.Error("expected an error when no token can be obtained")
+	}
+}
+
+func TestTokenFile(t *testing.T) {
+	srv := newTestServer(t, echoHeadersHandler("Authorization"))
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("first\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	client := newTestClient(t, &apiClientOpt{uri: srv.URL, headers: map[string]string{}, timeout: 2, token: "static", tokenFile: tokenFile})
+	if got, _ := client.sendRequest(context.Background(), "", "GET", "/", "", map[string]string{}); got != "Bearer first" {
+		t.Errorf("got Authorization %q", got)
+	}
+
+	// A rotated token is used by the next request.
+	if err := os.WriteFile(tokenFile, []byte("second"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := client.sendRequest(context.Background(), "", "GET", "/", "", map[string]string{}); got != "Bearer second" {
+		t.Errorf("got Authorization %q after rotation", got)
+	}
+
+	if err := os.Remove(tokenFile); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.sendRequest(context.Background(), "", "GET", "/", "", map[string]string{}); err == nil {
+		t.Error("expected an error when the token file is missing")
+	}
+}
+
+func TestStaticTokenAndBasicAuthPrecedence(t *testing.T) {
+	srv := newTestServer(t, echoHeadersHandler("Authorization"))
+
+	client := newTestClient(t, &apiClientOpt{uri: srv.URL, headers: map[string]string{}, timeout: 2, token: "static"})
+	if got, _ := client.sendRequest(context.Background(), "", "GET", "/", "", map[string]string{}); got != "Bearer static" {
+		t.Errorf("got Authorization %q", got)
+	}
+
+	client = newTestClient(t, &apiClientOpt{uri: srv.URL, headers: map[string]string{}, timeout: 2, token: "static", username: "user", password: "pass"})
+	if got, _ := client.sendRequest(context.Background(), "", "GET", "/", "", map[string]string{}); got != "Basic dXNlcjpwYXNz" {
+		t.Errorf("expected basic auth to win, got Authorization %q", got)
+	}
+}
+
+var sigv4AuthorizationRegexp = regexp.MustCompile(`^AWS4-HMAC-SHA256 Credential=([^/]+)/(\d{8})/([^/]+)/([^/]+)/aws4_request, SignedHeaders=([^,]+), Signature=([0-9a-f]+)$`)
+
+// verifySigV4 recomputes the signature of a received request with the known secret key.
+func verifySigV4(r *http.Request, secretKey string) error {
+	m := sigv4AuthorizationRegexp.FindStringSubmatch(r.Header.Get("Authorization"))
+	if m == nil {
+		return fmt.Errorf("unexpected Authorization header %q", r.Header.Get("Authorization"))
+	}
+	accessKey, region, service, signedHeaders, signature := m[1], m[3], m[4], m[5], m[6]
+	signingTime, err := time.Parse("20060102T150405Z", r.Header.Get("X-Amz-Date"))
+	if err != nil {
+		return err
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	hash := sha256.Sum256(body)
+
+	req, err := http.NewRequest(r.Method, "http://"+r.Host+r.URL.RequestURI(), nil)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = r.ContentLength
+	for _, h := range strings.Split(signedHeaders, ";") {
+		if h != "host" && h != "content-length" {
+			req.Header.Set(h, r.Header.Get(h))
+		}
+	}
+	creds := aws.Credentials{AccessKeyID: accessKey, SecretAccessKey: secretKey}
+	if err := v4.NewSigner().SignHTTP(context.Background(), creds, req, hex.EncodeToString(hash[:]), service, region, signingTime); err != nil {
+		return err
+	}
+	if want := sigv4AuthorizationRegexp.FindStringSubmatch(req.Header.Get("Authorization"))[6]; want != signature {
+		return fmt.Errorf("signature mismatch")
+	}
+	if region != "eu-west-1" || service != "execute-api" || !strings.Contains(signedHeaders, "x-scope-orgid") {
+		return fmt.Errorf("unexpected signing scope %s/%s (headers %s)", region, service, signedHeaders)
+	}
+	return nil
+}
+
+func TestSigV4(t *testing.T) {
+	srv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := verifySigV4(r, "secret"); err != nil {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(err.Error()))
+		}
+	}))
+
+	cfg, err := expandSigV4([]interface{}{map[string]interface{}{
+		"region":     "eu-west-1",
+		"access_key": "AKIDEXAMPLE",
+		"secret_key": "secret",
+		"profile":    "",
+		"role_arn":   "",
+		"service":    "execute-api",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := newTestClient(t, &apiClientOpt{uri: srv.URL, headers: map[string]string{"X-Scope-OrgID": "tenant-1"}, timeout: 2, token: "ignored", sigv4: cfg})
+
+	if _, err := client.sendRequest(context.Background(), "ruler", "GET", rulesGroupPath("my namespace", "group"), "", map[string]string{}); err != nil {
+		t.Errorf("GET: %v", err)
+	}
+	if _, err := client.sendRequest(context.Background(), "ruler", "POST", rulesNamespacePath("ns"), "name: group\nrules: []\n", map[string]string{contentTypeHeader: contentTypeYAML}); err != nil {
+		t.Errorf("POST: %v", err)
+	}
+
+	// A wrong secret key is detected by the server.
+	cfg.SecretKey = "wrong"
+	client = newTestClient(t, &apiClientOpt{uri: srv.URL, headers: map[string]string{}, timeout: 2, sigv4: cfg})
+	if _, err := client.sendRequest(context.Background(), "", "GET", "/", "", map[string]string{}); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("expected a signature mismatch, got %v", err)
+	}
+}
+
+func TestExpandSigV4(t *testing.T) {
+	if cfg, err := expandSigV4(nil); cfg != nil || err != nil {
+		t.Errorf("expected no config without block, got %v, %v", cfg, err)
+	}
+	_, err := expandSigV4([]interface{}{map[string]interface{}{
+		"region": "eu-west-1", "access_key": "AKIDEXAMPLE", "secret_key": "", "profile": "", "role_arn": "", "service": "aps",
+	}})
+	if err == nil {
+		t.Error("expected an error for an access key without secret key")
+	}
+}
@@ -0,0 +1,166 @@
+package mimir
+
+import (
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// componentNames are the components that may be configured with their own provider block.
+var componentNames = []string{
+	"ruler",
+	"alertmanager",
+	"distributor",
+	"query_frontend",
+	"ingester",
+	"store_gateway",
+	"compactor",
+}
+
+func componentSchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"uri": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Base url of the component. Defaults to the matching `<component>_uri`, then `uri`.",
+			},
+			"token": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "When set, will use this token for Bearer auth to the component.",
+			},
+			"token_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "When set, will read the Bearer token from this file before each request to the component.",
+			},
+			"oauth2": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "When set, will use OAuth2 client credentials to get a Bearer token for the component.",
+				Elem:        oauth2Schema(),
+			},
+			"sigv4": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "When set, will sign every request to the component with AWS Signature Version 4.",
+				Elem:        sigv4Schema(),
+			},
+			"username": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "When set, will use this username for BASIC auth to the component.",
+			},
+			"password": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "When set, will use this password for BASIC auth to the component.",
+			},
+			"headers": {
+				Type:        schema.TypeMap,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Optional:    true,
+				Description: "A map of header names and values to set on the requests to the component, merged over the provider `headers`.",
+			},
+			"insecure": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "When using https, this disables TLS verification of the component host. Defaults to the provider `insecure`.",
+			},
+			"cert": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Client cert (filepath or inline) for TLS client authentication to the component.",
+			},
+			"key": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Client key (filepath or inline) for TLS client authentication to the component.",
+			},
+			"ca": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Client ca (filepath, directory of PEM files or inline) to verify the component host.",
+			},
+			"timeout": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "When set, will cause requests to the component taking longer than this time (in seconds) to be aborted.",
+			},
+		},
+	}
+}
+
+// expandComponentOpt reads a component block over the provider options base; it returns nil
+// when the block is absent. Unset settings are inherited, except authentication: a block
+// setting any authentication method replaces the provider one entirely. insecure is the
+// raw configuration of the block insecure, as a false bool cannot be told from an unset one.
+func expandComponentOpt(v []interface{}, insecure cty.Value, base *apiClientOpt) (*apiClientOpt, error) {
+	if len(v) == 0 || v[0] == nil {
+		return nil, nil
+	}
+	data := v[0].(map[string]interface{})
+
+	opt := *base
+	opt.uri = data["uri"].(string)
+	opt.components = nil
+
+	headers := make(map[string]string)
+	for k, v := range data["headers"].(map[string]interface{}) {
+		headers[k] = v.(string)
+	}
+	opt.headers = headers
+
+	if cert := data["cert"].(string); cert != "" {
+		opt.cert = cert
+	}
+	if key := data["key"].(string); key != "" {
+		opt.key = key
+	}
+	if ca := data["ca"].(string); ca != "" {
+		opt.ca = ca
+	}
+	if insecure.IsKnown() && !insecure.IsNull() {
+		opt.insecure = insecure.True()
+	}
+	if timeout := data["timeout"].(int); timeout > 0 {
+		opt.timeout = timeout
+	}
+
+	sigv4, err := expandSigV4(data["sigv4"].([]interface{}))
+	if err != nil {
+		return nil, err
+	}
+	auth := apiClientOpt{
+		token:     data["token"].(string),
+		tokenFile: data["token_file"].(string),
+		oauth2:    expandOAuth2(data["oauth2"].([]interface{})),
+		sigv4:     sigv4,
+		username:  data["username"].(string),
+		password:  data["password"].(string),
+	}
+	if auth.token != "" || auth.tokenFile != "" || auth.oauth2 != nil || auth.sigv4 != nil || auth.username != "" || auth.password != "" {
+		opt.token = auth.token
+		opt.tokenFile = auth.tokenFile
+		opt.oauth2 = auth.oauth2
+		opt.sigv4 = auth.sigv4
+		opt.username = auth.username
+		opt.password = auth.password
+	}
+
+	return &opt, nil
+}
+
+// componentInsecure returns the raw configuration of the insecure of the name block, null
+// when the block does not set it.
+func componentInsecure(d *schema.ResourceData, name string) cty.Value {
+	v, diags := d.GetRawConfigAt(cty.GetAttrPath(name).IndexInt(0).GetAttr("insecure"))
+	if diags.HasError() {
+		return cty.NullVal(cty.Bool)
+	}
+	return v
+}
@@ -0,0 +1,171 @@
+package mimir
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func componentBlock(values map[string]interface{}) []interface{} {
+	block := map[string]interface{}{
+		"uri":        "",
+		"token":      "",
+		"token_file": "",
+		"oauth2":     []interface{}{},
+		"sigv4":      []interface{}{},
+		"username":   "",
+		"password":   "",
+		"headers":    map[string]interface{}{},
+		"insecure":   false,
+		"cert":       "",
+		"key":        "",
+		"ca":         "",
+		"timeout":    0,
+	}
+	for k, v := range values {
+		block[k] = v
+	}
+	return []interface{}{block}
+}
+
+func TestComponentClients(t *testing.T) {
+	// The servers reply with the Authorization and X-Team headers they received.
+	rulerSrv, rulerCA := newTestTLSServer(t, echoHeadersHandler("Authorization", "X-Team"), 1)
+	amSrv, amCA := newTestTLSServer(t, echoHeadersHandler("Authorization", "X-Team"), 2)
+
+	opt := &apiClientOpt{
+		uri:     rulerSrv.URL,
+		token:   "provider",
+		headers: map[string]string{"X-Team": "provider"},
+		timeout: 2,
+	}
+	rulerOpt, err := expandComponentOpt(componentBlock(map[string]interface{}{
+		"ca":    string(rulerCA),
+		"token": "ruler",
+	}), cty.NullVal(cty.Bool), opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	amOpt, err := expandComponentOpt(componentBlock(map[string]interface{}{
+		"uri":      amSrv.URL,
+		"ca":       string(amCA),
+		"username": "user",
+		"password": "pass",
+		"headers":  map[string]interface{}{"X-Team": "alerting"},
+	}), cty.NullVal(cty.Bool), opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opt.components = map[string]*apiClientOpt{"ruler": rulerOpt, "alertmanager": amOpt}
+
+	client := newTestClient(t, opt)
+
+	got, err := client.sendRequest(context.Background(), "ruler", "GET", "/", "", map[string]string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Bearer ruler|provider"; got != want {
+		t.Errorf("ruler: got %q, want %q", got, want)
+	}
+
+	got, err = client.sendRequest(context.Background(), "alertmanager", "GET", "/", "", map[string]string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Basic dXNlcjpwYXNz|alerting"; got != want {
+		t.Errorf("alertmanager: got %q, want %q", got, want)
+	}
+
+	got, err = client.sendRequest(context.Background(), "alertmanager", "GET", "/", "", map[string]string{"X-Team": "resource"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Basic dXNlcjpwYXNz|resource"; got != want {
+		t.Errorf("alertmanager with resource headers: got %q, want %q", got, want)
+	}
+
+	// The provider client does not trust the test CA.
+	if _, err := client.sendRequest(context.Background(), "distributor", "GET", "/", "", map[string]string{}); err == nil {
+		t.Error("expected a TLS error without a distributor block")
+	}
+}
+
+func TestExpandComponentOptInherits(t *testing.T) {
+	base := &apiClientOpt{
+		uri:     "http://mimir",
+		token:   "provider",
+		ca:      "ca.pem",
+		headers: map[string]string{"X-Team": "provider"},
+		timeout: 60,
+	}
+
+	opt, err := expandComponentOpt(componentBlock(map[string]interface{}{"timeout": 5}), cty.NullVal(cty.Bool), base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opt.uri != "" || opt.token != "provider" || opt.ca != "ca.pem" || opt.timeout != 5 {
+		t.Errorf("unexpected options %+v", opt)
+	}
+
+	opt, err = expandComponentOpt(componentBlock(map[string]interface{}{"token_file": "/token"}), cty.NullVal(cty.Bool), base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opt.token != "" || opt.tokenFile != "/token" || opt.timeout != 60 {
+		t.Errorf("expected the block authentication to replace the provider one, got %+v", opt)
+	}
+
+	// insecure is only inherited when the block does not set it.
+	base.insecure = true
+	if opt, _ := expandComponentOpt(componentBlock(nil), cty.NullVal(cty.Bool), base); !opt.insecure {
+		t.Error("expected insecure to be inherited")
+	}
+	if opt, _ := expandComponentOpt(componentBlock(nil), cty.False, base); opt.insecure {
+		t.Error("expected the block insecure = false to override the provider one")
+	}
+
+	if opt, _ := expandComponentOpt([]interface{}{}, cty.NullVal(cty.Bool), base); opt != nil {
+		t.Errorf("expected no options without a block, got %+v", opt)
+	}
+}
+
+// configObject returns the object of type ty with values, its other attributes null.
+func configObject(ty cty.Type, values map[string]cty.Value) cty.Value {
+	attrs := make(map[string]cty.Value)
+	for name, t := range ty.AttributeTypes() {
+		attrs[name] = cty.NullVal(t)
+	}
+	for name, v := range values {
+		attrs[name] = v
+	}
+	return cty.ObjectVal(attrs)
+}
+
+func TestProviderComponentInsecure(t *testing.T) {
+	srv, _ := newTestTLSServer(t, echoHeadersHandler("X-Scope-OrgID"), 1)
+
+	p := Provider("test")()
+	configSchema := schema.InternalMap(p.Schema).CoreConfigSchema()
+	ty := configSchema.ImpliedType()
+	rulerTy := ty.AttributeType("ruler").ElementType()
+	config := configObject(ty, map[string]cty.Value{
+		"uri":      cty.StringVal(srv.URL),
+		"org_id":   cty.StringVal("tenant-1"),
+		"insecure": cty.True,
+		"ruler":    cty.ListVal([]cty.Value{configObject(rulerTy, map[string]cty.Value{"insecure": cty.False})}),
+	})
+	if diags := p.Configure(context.Background(), terraform.NewResourceConfigShimmed(config, configSchema)); diags.HasError() {
+		t.Fatalf("configure: %v", diags)
+	}
+	client := p.Meta().(*apiClient)
+
+	if _, err := client.sendRequest(context.Background(), "distributor", "GET", "/", "", map[string]string{}); err != nil {
+		t.Errorf("expected the provider insecure to be used without a block, got %v", err)
+	}
+	if _, err := client.sendRequest(context.Background(), "ruler", "GET", "/", "", map[string]string{}); err == nil {
+		t.Error("expected a TLS error with the ruler block insecure = false")
+	}
+}
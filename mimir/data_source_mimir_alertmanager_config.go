@@ -17,7 +17,7 @@ func dataSourcemimirAlertmanagerConfig() *schema.Resource {
 }
 
 func dataSourcemimirAlertmanagerConfigRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*apiClient)
+	client := meta.(*apiClient).forResource(d)
 	name := d.Get("name").(string)
 	orgID := d.Get("org_id").(string)
 
@@ -25,7 +25,7 @@ func dataSourcemimirAlertmanagerConfigRead(ctx context.Context, d *schema.Resour
 	if orgID != "" {
 		headers["X-Scope-OrgID"] = orgID
 	}
-	resp, err := client.sendRequest("alertmanager", "GET", apiAlertsPath, "", headers)
+	resp, err := client.sendRequest(ctx, "alertmanager", "GET", apiAlertsPath, "", headers)
 	baseMsg := "Cannot read alertmanager config"
 	err = handleHTTPError(err, baseMsg)
 	if err != nil {
@@ -0,0 +1,84 @@
+package mimir
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourcemimirBuildInfo() *schema.Resource {
+	return &schema.Resource{
+		Description: "Reads the build information of the Mimir cluster (`/api/v1/status/buildinfo` on the ruler uri).",
+		ReadContext: dataSourcemimirBuildInfoRead,
+
+		Schema: map[string]*schema.Schema{
+			"application": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Application name, e.g. `Grafana Mimir`.",
+			},
+			"version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Mimir version.",
+			},
+			"revision": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Git revision Mimir was built from.",
+			},
+			"branch": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Git branch Mimir was built from.",
+			},
+			"go_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Go version Mimir was built with.",
+			},
+			"features": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "Features reported by Mimir, e.g. `ruler_config_api`.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"request_headers": requestHeadersSchema("data source"),
+			"auth":            requestAuthSchema("data source"),
+		}, /* End schema */
+	}
+}
+
+func dataSourcemimirBuildInfoRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).forResource(d)
+
+	info, err := client.fetchBuildInfo(ctx)
+	baseMsg := "Cannot read build info"
+	err = handleHTTPError(err, baseMsg)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(info.Version)
+	if err := d.Set("application", info.Application); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("version", info.Version); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("revision", info.Revision); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("branch", info.Branch); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("go_version", info.GoVersion); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("features", info.Features); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
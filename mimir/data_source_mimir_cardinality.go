@@ -0,0 +1,349 @@
+package mimir
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	apiCardinalityLabelNamesPath   = "/api/v1/cardinality/label_names"
+	apiCardinalityLabelValuesPath  = "/api/v1/cardinality/label_values"
+	apiCardinalityActiveSeriesPath = "/api/v1/cardinality/active_series"
+)
+
+type cardinalityLabelNames struct {
+	LabelValuesCountTotal int `json:"label_values_count_total"`
+	LabelNamesCount       int `json:"label_names_count"`
+	Cardinality           []struct {
+		LabelName        string `json:"label_name"`
+		LabelValuesCount int    `json:"label_values_count"`
+	} `json:"cardinality"`
+}
+
+type cardinalityLabelValues struct {
+	SeriesCountTotal int `json:"series_count_total"`
+	Labels           []struct {
+		LabelName        string `json:"label_name"`
+		LabelValuesCount int    `json:"label_values_count"`
+		SeriesCount      int    `json:"series_count"`
+		Cardinality      []struct {
+			LabelValue  string `json:"label_value"`
+			SeriesCount int    `json:"series_count"`
+		} `json:"cardinality"`
+	} `json:"labels"`
+}
+
+type cardinalityActiveSeries struct {
+	Data []map[string]string `json:"data"`
+}
+
+// cardinalitySchema returns the arguments shared by the cardinality data sources.
+func cardinalitySchema(extra map[string]*schema.Schema) map[string]*schema.Schema {
+	s := map[string]*schema.Schema{
+		orgIDKey: {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: orgIDDescription,
+		},
+		"selector": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Series selector restricting the analysis, e.g. `{job=\"api\"}`.",
+		},
+		"limit": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Description:  "Maximum number of items returned. Defaults to the Mimir default (20).",
+			ValidateFunc: validation.IntAtLeast(0),
+		},
+		"count_method": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "inmemory",
+			Description:  "How series are counted: `inmemory` (series in the ingesters memory) or `active` (series that received samples recently).",
+			ValidateFunc: validation.StringInSlice([]string{"inmemory", "active"}, false),
+		},
+		"request_headers": requestHeadersSchema("data source"),
+		"auth":            requestAuthSchema("data source"),
+	}
+	for k, v := range extra {
+		s[k] = v
+	}
+	return s
+}
+
+func dataSourcemimirCardinalityLabelNames() *schema.Resource {
+	return &schema.Resource{
+		Description: "Reads the label names with the highest number of values of a tenant (`/api/v1/cardinality/label_names`).",
+		ReadContext: dataSourcemimirCardinalityLabelNamesRead,
+
+		Schema: cardinalitySchema(map[string]*schema.Schema{
+			"label_values_count_total": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Total number of label values.",
+			},
+			"label_names_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Total number of label names.",
+			},
+			"cardinality": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Label names, sorted by decreasing number of values.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"label_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"label_values_count": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+		}), /* End schema */
+	}
+}
+
+func dataSourcemimirCardinalityLabelValues() *schema.Resource {
+	return &schema.Resource{
+		Description: "Reads the label values with the highest number of series for some label names of a tenant (`/api/v1/cardinality/label_values`).",
+		ReadContext: dataSourcemimirCardinalityLabelValuesRead,
+
+		Schema: cardinalitySchema(map[string]*schema.Schema{
+			"label_names": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Description: "Label names to analyze, e.g. `[\"__name__\"]` for the top metric names.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"series_count_total": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Total number of series.",
+			},
+			"labels": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Cardinality of each label name.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"label_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"label_values_count": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"series_count": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"cardinality": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "Label values, sorted by decreasing number of series.",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"label_value": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"series_count": {
+										Type:     schema.TypeInt,
+										Computed: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}), /* End schema */
+	}
+}
+
+func dataSourcemimirCardinalityActiveSeries() *schema.Resource {
+	s := cardinalitySchema(map[string]*schema.Schema{
+		"series": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: "Label sets of the active series.",
+			Elem: &schema.Schema{
+				Type: schema.TypeMap,
+				Elem: &schema.Schema{Type: schema.TypeString},
+			},
+		},
+		"series_count": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "Number of active series matching the selector.",
+		},
+	})
+	// The active series API has no count method and requires a selector.
+	delete(s, "count_method")
+	s["selector"].Optional = false
+	s["selector"].Required = true
+	s["limit"].Description = "Maximum number of series returned. All matching series are returned if not set."
+
+	return &schema.Resource{
+		Description: "Reads the active series matching a selector of a tenant (`/api/v1/cardinality/active_series`).",
+		ReadContext: dataSourcemimirCardinalityActiveSeriesRead,
+		Schema:      s,
+	}
+}
+
+// cardinalityParams returns the limit and count_method request parameters.
+func cardinalityParams(d *schema.ResourceData) url.Values {
+	params := url.Values{}
+	if limit := d.Get("limit").(int); limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+	params.Set("count_method", d.Get("count_method").(string))
+	return params
+}
+
+// cardinalityRequest runs a cardinality API request with the selector and sets the data
+// source ID.
+func cardinalityRequest(ctx context.Context, d *schema.ResourceData, client *apiClient, path string, params url.Values, out interface{}) error {
+	orgID := d.Get(orgIDKey).(string)
+
+	headers := map[string]string{"Content-Type": "application/x-www-form-urlencoded"}
+	if orgID != "" {
+		headers["X-Scope-OrgID"] = orgID
+	}
+
+	if selector := d.Get("selector").(string); selector != "" {
+		params.Set("selector", selector)
+	}
+
+	jobraw, err := client.sendRequest(ctx, "query_frontend", "POST", path, params.Encode(), headers)
+	baseMsg := "Cannot read cardinality"
+	err = handleHTTPError(err, baseMsg)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal([]byte(jobraw), out); err != nil {
+		return fmt.Errorf("unable to decode cardinality response: %v", err)
+	}
+
+	d.SetId(fmt.Sprintf("%x", sha256.Sum256([]byte(orgID+"\n"+path+"?"+params.Encode()))))
+	return nil
+}
+
+func dataSourcemimirCardinalityLabelNamesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).forResource(d)
+
+	var data cardinalityLabelNames
+	if err := cardinalityRequest(ctx, d, client, apiCardinalityLabelNamesPath, cardinalityParams(d), &data); err != nil {
+		return diag.FromErr(err)
+	}
+
+	var cardinality []interface{}
+	for _, c := range data.Cardinality {
+		cardinality = append(cardinality, map[string]interface{}{
+			"label_name":         c.LabelName,
+			"label_values_count": c.LabelValuesCount,
+		})
+	}
+	if err := d.Set("label_values_count_total", data.LabelValuesCountTotal); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("label_names_count", data.LabelNamesCount); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("cardinality", cardinality); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func dataSourcemimirCardinalityLabelValuesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).forResource(d)
+
+	params := cardinalityParams(d)
+	for _, name := range expandStringArray(d.Get("label_names").([]interface{})) {
+		params.Add("label_names[]", name)
+	}
+
+	var data cardinalityLabelValues
+	if err := cardinalityRequest(ctx, d, client, apiCardinalityLabelValuesPath, params, &data); err != nil {
+		return diag.FromErr(err)
+	}
+
+	var labels []interface{}
+	for _, l := range data.Labels {
+		var cardinality []interface{}
+		for _, c := range l.Cardinality {
+			cardinality = append(cardinality, map[string]interface{}{
+				"label_value":  c.LabelValue,
+				"series_count": c.SeriesCount,
+			})
+		}
+		labels = append(labels, map[string]interface{}{
+			"label_name":         l.LabelName,
+			"label_values_count": l.LabelValuesCount,
+			"series_count":       l.SeriesCount,
+			"cardinality":        cardinality,
+		})
+	}
+	if err := d.Set("series_count_total", data.SeriesCountTotal); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("labels", labels); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func dataSourcemimirCardinalityActiveSeriesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).forResource(d)
+
+	var data cardinalityActiveSeries
+	if err := cardinalityRequest(ctx, d, client, apiCardinalityActiveSeriesPath, url.Values{}, &data); err != nil {
+		return diag.FromErr(err)
+	}
+
+	// The API returns series in no particular order; sort them to keep the plan stable.
+	keys := make([]string, len(data.Data))
+	order := make([]int, len(data.Data))
+	for i, labels := range data.Data {
+		keys[i] = fmt.Sprint(labels)
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool { return keys[order[a]] < keys[order[b]] })
+
+	series := make([]interface{}, 0, len(data.Data))
+	for _, i := range order {
+		series = append(series, data.Data[i])
+	}
+	if limit := d.Get("limit").(int); limit > 0 && len(series) > limit {
+		series = series[:limit]
+	}
+	if err := d.Set("series", series); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("series_count", len(data.Data)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package mimir
+
+import (
+	"net/http"
+	"testing"
+)
+
+// cardinalityTestHandler serves the cardinality APIs of the query-frontend.
+func cardinalityTestHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/prometheus"+apiCardinalityLabelNamesPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Scope-OrgID") != "tenant-1" || r.FormValue("count_method") != "active" || r.FormValue("limit") != "2" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"label_values_count_total":120,"label_names_count":8,"cardinality":[` +
+			`{"label_name":"__name__","label_values_count":100},{"label_name":"instance","label_values_count":12}]}`))
+	})
+	mux.HandleFunc("/prometheus"+apiCardinalityLabelValuesPath, func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || len(r.PostForm["label_names[]"]) != 2 || r.FormValue("selector") != `{job="api"}` || r.FormValue("count_method") != "inmemory" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"series_count_total":50,"labels":[` +
+			`{"label_name":"__name__","label_values_count":2,"series_count":50,"cardinality":[{"label_value":"up","series_count":40},{"label_value":"build_info","series_count":10}]},` +
+			`{"label_name":"job","label_values_count":1,"series_count":50,"cardinality":[{"label_value":"api","series_count":50}]}]}`))
+	})
+	mux.HandleFunc("/prometheus"+apiCardinalityActiveSeriesPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("selector") != `{__name__="up"}` || r.FormValue("count_method") != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":[{"__name__":"up","instance":"b"},{"__name__":"up","instance":"a"},{"__name__":"up","instance":"c"}]}`))
+	})
+	return mux
+}
+
+func TestDataSourceCardinalityLabelNamesRead(t *testing.T) {
+	client := newTestAPIClient(t, cardinalityTestHandler())
+	d := readTestDataSource(t, dataSourcemimirCardinalityLabelNames(), client, map[string]interface{}{
+		orgIDKey: "tenant-1", "limit": 2, "count_method": "active",
+	})
+	if d.Get("label_names_count") != 8 || d.Get("cardinality.#") != 2 || d.Get("cardinality.0.label_name") != "__name__" || d.Get("cardinality.1.label_values_count") != 12 {
+		t.Errorf("unexpected label names %v", d.Get("cardinality"))
+	}
+}
+
+func TestDataSourceCardinalityLabelValuesRead(t *testing.T) {
+	client := newTestAPIClient(t, cardinalityTestHandler())
+	d := readTestDataSource(t, dataSourcemimirCardinalityLabelValues(), client, map[string]interface{}{
+		"label_names": []interface{}{"__name__", "job"}, "selector": `{job="api"}`,
+	})
+	if d.Get("series_count_total") != 50 || d.Get("labels.#") != 2 || d.Get("labels.0.cardinality.0.label_value") != "up" || d.Get("labels.0.cardinality.0.series_count") != 40 {
+		t.Errorf("unexpected label values %v", d.Get("labels"))
+	}
+}
+
+func TestDataSourceCardinalityActiveSeriesRead(t *testing.T) {
+	client := newTestAPIClient(t, cardinalityTestHandler())
+	d := readTestDataSource(t, dataSourcemimirCardinalityActiveSeries(), client, map[string]interface{}{
+		"selector": `{__name__="up"}`, "limit": 2,
+	})
+	if d.Get("series_count") != 3 || d.Get("series.#") != 2 {
+		t.Fatalf("unexpected active series %v", d.Get("series"))
+	}
+	if d.Get("series.0.instance") != "a" || d.Get("series.1.instance") != "b" {
+		t.Errorf("series are not sorted: %v", d.Get("series"))
+	}
+}
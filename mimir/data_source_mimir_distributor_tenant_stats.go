@@ -1,23 +1,41 @@
 package mimir
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
-	"math"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+const apiAllUserStatsPath = "/all_user_stats"
+
 type Stats struct {
 	User            string  `json:"UserID"`
 	Series          int     `json:"numSeries"`
+	ActiveSeries    int     `json:"activeSeries"`
 	TotalIngestRate float64 `json:"ingestionRate"`
 	APIIngestRate   float64 `json:"APIIngestionRate"`
 	RuleIngestRate  float64 `json:"RuleIngestionRate"`
+	// fields holds every field returned, including the ones without a dedicated attribute.
+	fields map[string]string
+}
+
+// distributorStatsSortKeys maps the sort_by values to the stat they sort on.
+var distributorStatsSortKeys = map[string]func(Stats) float64{
+	"series":            func(s Stats) float64 { return float64(s.Series) },
+	"active_series":     func(s Stats) float64 { return float64(s.ActiveSeries) },
+	"total_ingest_rate": func(s Stats) float64 { return s.TotalIngestRate },
+	"api_ingest_rate":   func(s Stats) float64 { return s.APIIngestRate },
+	"rule_ingest_rate":  func(s Stats) float64 { return s.RuleIngestRate },
 }
 
 func dataSourcemimirDistributorTenantStats() *schema.Resource {
@@ -37,6 +55,43 @@ func dataSourcemimirDistributorTenantStats() *schema.Resource {
 				ForceNew:    true,
 				Optional:    true,
 			},
+			"users": {
+				Type:        schema.TypeList,
+				Description: "Only return the stats of these users.",
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"user_regex": {
+				Type:         schema.TypeString,
+				Description:  "Only return the stats of the users matching this regular expression (fully anchored).",
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+			},
+			"sort_by": {
+				Type:         schema.TypeString,
+				Description:  "Field the stats are sorted by: `user`, `series`, `active_series`, `total_ingest_rate`, `api_ingest_rate` or `rule_ingest_rate`.",
+				Optional:     true,
+				Default:      "user",
+				ValidateFunc: validation.StringInSlice([]string{"user", "series", "active_series", "total_ingest_rate", "api_ingest_rate", "rule_ingest_rate"}, false),
+			},
+			"sort_order": {
+				Type:         schema.TypeString,
+				Description:  "Sort order: `asc` or `desc`. Defaults to `asc` when sorting by user, `desc` otherwise.",
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"asc", "desc"}, false),
+			},
+			"top": {
+				Type:         schema.TypeInt,
+				Description:  "Only return the first N stats, after filtering and sorting.",
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"replication_factor": {
+				Type:         schema.TypeInt,
+				Description:  "Ingesters replication factor, used to compute `series_per_replica`. Defaults to 1.",
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
 			"stats": {
 				Type:        schema.TypeList,
 				Description: "Stats list, does not account for replication factor",
@@ -48,40 +103,61 @@ func dataSourcemimirDistributorTenantStats() *schema.Resource {
 							Computed: true,
 						},
 						"series": {
-							Type:     schema.TypeInt,
-							Computed: true,
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "In-memory series, summed over the ingesters (including replicas).",
+						},
+						"series_per_replica": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "`series` divided by `replication_factor`.",
+						},
+						"active_series": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Active series, when reported by Mimir.",
 						},
 						"total_ingest_rate": {
-							Type:     schema.TypeFloat,
-							Computed: true,
+							Type:        schema.TypeFloat,
+							Computed:    true,
+							Description: "Samples ingested per second.",
 						},
 						"api_ingest_rate": {
-							Type:     schema.TypeFloat,
-							Computed: true,
+							Type:        schema.TypeFloat,
+							Computed:    true,
+							Description: "Samples ingested per second through the push API.",
 						},
 						"rule_ingest_rate": {
-							Type:     schema.TypeFloat,
-							Computed: true,
+							Type:        schema.TypeFloat,
+							Computed:    true,
+							Description: "Samples ingested per second by the ruler.",
+						},
+						"fields": {
+							Type:        schema.TypeMap,
+							Computed:    true,
+							Description: "Every field returned by Mimir for the user, as strings.",
+							Elem:        &schema.Schema{Type: schema.TypeString},
 						},
 					},
 				},
 			},
+			"request_headers": requestHeadersSchema("data source"),
+			"auth":            requestAuthSchema("data source"),
 		}, /* End schema */
 
 	}
 }
 
 func dataSourcemimirDistributorTenantStatsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*apiClient)
-	user := d.Get("user").(string)
+	client := meta.(*apiClient).forResource(d)
 	orgID := d.Get(orgIDKey).(string)
 
-	headers := map[string]string{"Accept": "json"}
+	headers := map[string]string{"Accept": "application/json"}
 	if orgID != "" {
 		headers["X-Scope-OrgID"] = orgID
 	}
 
-	jobraw, err := client.sendRequest("distributor", "GET", "/all_user_stats", "", headers)
+	jobraw, err := client.sendRequest(ctx, "distributor", "GET", apiAllUserStatsPath, "", headers)
 
 	baseMsg := "Cannot read user stats"
 	err = handleHTTPError(err, baseMsg)
@@ -93,45 +169,136 @@ func dataSourcemimirDistributorTenantStatsRead(ctx context.Context, d *schema.Re
 		return diag.FromErr(err)
 	}
 
-	// unmarshal the json using json/encoding into Stats struct
-	var output []Stats
-	err = json.Unmarshal([]byte(jobraw), &output)
+	output, err := decodeDistributorStats(jobraw)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	output, err = filterDistributorStats(d, output)
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("unable to unmarshal json: %v", err))
+		return diag.FromErr(err)
 	}
 
-	var stats []map[string]interface{}
-	// transform the output into a list of maps
+	replicationFactor := d.Get("replication_factor").(int)
+	if replicationFactor == 0 {
+		replicationFactor = 1
+	}
+	stats := make([]map[string]interface{}, 0, len(output))
 	for _, stat := range output {
-		// trim float to 2 decimal places
-		stat.TotalIngestRate = math.Round(stat.TotalIngestRate*100) / 100
-		stat.APIIngestRate = math.Round(stat.APIIngestRate*100) / 100
-		stat.RuleIngestRate = math.Round(stat.RuleIngestRate*100) / 100
 		stats = append(stats, map[string]interface{}{
-			"user":              stat.User,
-			"series":            stat.Series,
-			"total_ingest_rate": stat.TotalIngestRate,
-			"api_ingest_rate":   stat.APIIngestRate,
-			"rule_ingest_rate":  stat.RuleIngestRate,
+			"user":               stat.User,
+			"series":             stat.Series,
+			"series_per_replica": stat.Series / replicationFactor,
+			"active_series":      stat.ActiveSeries,
+			"total_ingest_rate":  stat.TotalIngestRate,
+			"api_ingest_rate":    stat.APIIngestRate,
+			"rule_ingest_rate":   stat.RuleIngestRate,
+			"fields":             stat.fields,
 		})
 	}
 
-	// if user is specified then filter the stats
-	if user != "" {
-		var filteredStats []map[string]interface{}
-		for _, stat := range stats {
-			if stat["user"] == user {
-				filteredStats = append(filteredStats, stat)
+	if err := d.Set("stats", stats); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(distributorStatsID(d))
+
+	return nil
+}
+
+// decodeDistributorStats decodes the JSON user stats. The distributor renders an HTML page
+// when the Accept header is not honored, e.g. by a proxy rewriting it.
+func decodeDistributorStats(body string) ([]Stats, error) {
+	if strings.HasPrefix(strings.TrimSpace(body), "<") {
+		return nil, fmt.Errorf("unable to decode user stats: the distributor returned HTML instead of JSON, check that distributor_uri points to the distributor and that the Accept header reaches it")
+	}
+
+	var output []Stats
+	if err := json.Unmarshal([]byte(body), &output); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal json: %v", err)
+	}
+	var raw []map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader([]byte(body)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal json: %v", err)
+	}
+	for i := range output {
+		output[i].fields = make(map[string]string, len(raw[i]))
+		for k, v := range raw[i] {
+			switch value := v.(type) {
+			case string:
+				output[i].fields[k] = value
+			case json.Number:
+				output[i].fields[k] = value.String()
+			default:
+				b, _ := json.Marshal(value)
+				output[i].fields[k] = string(b)
 			}
 		}
-		stats = filteredStats
 	}
+	return output, nil
+}
 
-	if err := d.Set("stats", stats); err != nil {
-		return diag.FromErr(err)
+// filterDistributorStats applies the user filters, the sort and the top-N limit.
+func filterDistributorStats(d *schema.ResourceData, stats []Stats) ([]Stats, error) {
+	user := d.Get("user").(string)
+	users := make(map[string]bool)
+	for _, u := range expandStringArray(d.Get("users").([]interface{})) {
+		users[u] = true
+	}
+	var userRegex *regexp.Regexp
+	if expr := d.Get("user_regex").(string); expr != "" {
+		var err error
+		if userRegex, err = regexp.Compile("^(?:" + expr + ")$"); err != nil {
+			return nil, fmt.Errorf("invalid user_regex: %v", err)
+		}
 	}
 
-	d.SetId(id.UniqueId())
+	filtered := make([]Stats, 0, len(stats))
+	for _, stat := range stats {
+		if user != "" && stat.User != user {
+			continue
+		}
+		if len(users) > 0 && !users[stat.User] {
+			continue
+		}
+		if userRegex != nil && !userRegex.MatchString(stat.User) {
+			continue
+		}
+		filtered = append(filtered, stat)
+	}
 
-	return nil
+	sortBy := d.Get("sort_by").(string)
+	desc := d.Get("sort_order").(string) == "desc" || (d.Get("sort_order").(string) == "" && sortBy != "user")
+	key := distributorStatsSortKeys[sortBy]
+	sort.SliceStable(filtered, func(i, j int) bool {
+		a, b := filtered[i], filtered[j]
+		if key != nil && key(a) != key(b) {
+			return (key(a) < key(b)) != desc
+		}
+		if key == nil && desc {
+			return a.User > b.User
+		}
+		// Ties are broken by ascending user, to keep the output stable.
+		return a.User < b.User
+	})
+
+	if top := d.Get("top").(int); top > 0 && len(filtered) > top {
+		filtered = filtered[:top]
+	}
+	return filtered, nil
+}
+
+// distributorStatsID derives the data source ID from its arguments, so it is stable
+// across refreshes.
+func distributorStatsID(d *schema.ResourceData) string {
+	var b strings.Builder
+	for _, k := range []string{orgIDKey, "user", "user_regex", "sort_by", "sort_order"} {
+		fmt.Fprintf(&b, "%s=%s\n", k, d.Get(k).(string))
+	}
+	fmt.Fprintf(&b, "users=%s\n", strings.Join(expandStringArray(d.Get("users").([]interface{})), ","))
+	fmt.Fprintf(&b, "top=%s\n", strconv.Itoa(d.Get("top").(int)))
+	fmt.Fprintf(&b, "replication_factor=%s\n", strconv.Itoa(d.Get("replication_factor").(int)))
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(b.String())))
 }
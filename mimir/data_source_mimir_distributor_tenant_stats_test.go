@@ -0,0 +1,96 @@
+package mimir
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// distributorStatsTestHandler serves body as the tenant stats of the distributor.
+func distributorStatsTestHandler(body string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/distributor"+apiAllUserStatsPath || r.Header.Get("Accept") != "application/json" {
+			w.WriteHeader(http.StatusNotAcceptable)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	})
+}
+
+const distributorStatsBody = `[
+	{"userID":"team-b","ingestionRate":150.123456,"numSeries":3000,"APIIngestionRate":100,"RuleIngestionRate":50.123456,"activeSeries":2500},
+	{"userID":"team-a","ingestionRate":150.123456,"numSeries":9000,"APIIngestionRate":150.123456,"RuleIngestionRate":0},
+	{"userID":"infra","ingestionRate":10,"numSeries":600,"APIIngestionRate":10,"RuleIngestionRate":0}
+]`
+
+func statsUsers(d *schema.ResourceData) []string {
+	var users []string
+	for _, s := range d.Get("stats").([]interface{}) {
+		users = append(users, s.(map[string]interface{})["user"].(string))
+	}
+	return users
+}
+
+func TestDataSourceDistributorTenantStatsRead(t *testing.T) {
+	client := newTestAPIClient(t, distributorStatsTestHandler(distributorStatsBody))
+
+	d := readTestDataSource(t, dataSourcemimirDistributorTenantStats(), client, map[string]interface{}{"replication_factor": 3})
+	if got := statsUsers(d); len(got) != 3 || got[0] != "infra" || got[1] != "team-a" || got[2] != "team-b" {
+		t.Errorf("expected the users sorted by name, got %v", got)
+	}
+	teamB := d.Get("stats").([]interface{})[2].(map[string]interface{})
+	if teamB["series"] != 3000 || teamB["series_per_replica"] != 1000 || teamB["active_series"] != 2500 ||
+		teamB["rule_ingest_rate"] != 50.123456 || teamB["fields"].(map[string]interface{})["RuleIngestionRate"] != "50.123456" {
+		t.Errorf("unexpected stats %v", teamB)
+	}
+
+	// The ID only depends on the arguments.
+	if again := readTestDataSource(t, dataSourcemimirDistributorTenantStats(), client, map[string]interface{}{"replication_factor": 3}); again.Id() != d.Id() {
+		t.Errorf("unstable ID %q != %q", again.Id(), d.Id())
+	}
+	if other := readTestDataSource(t, dataSourcemimirDistributorTenantStats(), client, map[string]interface{}{}); other.Id() == d.Id() {
+		t.Error("expected a different ID for different arguments")
+	}
+}
+
+func TestDataSourceDistributorTenantStatsFilterSort(t *testing.T) {
+	client := newTestAPIClient(t, distributorStatsTestHandler(distributorStatsBody))
+
+	for _, tc := range []struct {
+		raw  map[string]interface{}
+		want []string
+	}{
+		{map[string]interface{}{"user": "team-a"}, []string{"team-a"}},
+		{map[string]interface{}{"users": []interface{}{"infra", "team-b", "unknown"}}, []string{"infra", "team-b"}},
+		{map[string]interface{}{"user_regex": "team-.*"}, []string{"team-a", "team-b"}},
+		{map[string]interface{}{"user_regex": "team"}, nil},
+		{map[string]interface{}{"sort_by": "series"}, []string{"team-a", "team-b", "infra"}},
+		{map[string]interface{}{"sort_by": "series", "sort_order": "asc"}, []string{"infra", "team-b", "team-a"}},
+		{map[string]interface{}{"sort_by": "total_ingest_rate", "top": 2}, []string{"team-a", "team-b"}},
+		{map[string]interface{}{"sort_order": "desc", "top": 1}, []string{"team-b"}},
+	} {
+		d := readTestDataSource(t, dataSourcemimirDistributorTenantStats(), client, tc.raw)
+		got := statsUsers(d)
+		if len(got) != len(tc.want) {
+			t.Errorf("%v: got %v, want %v", tc.raw, got, tc.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Errorf("%v: got %v, want %v", tc.raw, got, tc.want)
+				break
+			}
+		}
+	}
+}
+
+func TestDataSourceDistributorTenantStatsHTML(t *testing.T) {
+	client := newTestAPIClient(t, distributorStatsTestHandler("<!DOCTYPE html><html><body><table></table></body></html>"))
+	d := schema.TestResourceDataRaw(t, dataSourcemimirDistributorTenantStats().Schema, map[string]interface{}{})
+	diags := dataSourcemimirDistributorTenantStatsRead(context.Background(), d, client)
+	if !diags.HasError() {
+		t.Fatal("expected an error for an HTML response")
+	}
+}
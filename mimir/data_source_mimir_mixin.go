@@ -0,0 +1,174 @@
+package mimir
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/google/go-jsonnet"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"gopkg.in/yaml.v3"
+)
+
+// mixinSnippet evaluates the mixin imported from %s with the config overrides merged into
+// its _config, and returns its rule and alert groups, null when not defined.
+const mixinSnippet = `local mixin = (import %s) + { _config+:: std.extVar('config') };
+{
+  rules: if std.objectHasAll(mixin, 'prometheusRules') then mixin.prometheusRules else null,
+  alerts: if std.objectHasAll(mixin, 'prometheusAlerts') then mixin.prometheusAlerts else null,
+}
+`
+
+func dataSourcemimirMixin() *schema.Resource {
+	return &schema.Resource{
+		Description: "Renders the `prometheusRules` and `prometheusAlerts` of a monitoring mixin with jsonnet into rule groups YAML for `mimir_rules`, without calling the Mimir API.",
+		ReadContext: dataSourcemimirMixinRead,
+
+		Schema: map[string]*schema.Schema{
+			"path": {
+				Type:        schema.TypeString,
+				Description: "Path of the jsonnet file of the mixin, e.g. `vendor/mimir-mixin/mixin.libsonnet`.",
+				Required:    true,
+			},
+			"jpath": {
+				Type:        schema.TypeList,
+				Description: "Library search paths of the jsonnet imports, e.g. the `vendor` directory of jsonnet-bundler. As with `jsonnet -J`, the last one takes precedence.",
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"config": {
+				Type:         schema.TypeString,
+				Description:  "JSON object merged into the mixin `_config`, e.g. `jsonencode({ alert_aggregation_labels = \"cluster, namespace\" })`.",
+				Optional:     true,
+				ValidateFunc: validation.StringIsJSON,
+			},
+			"content": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "YAML content of the recording then alerting rule groups, ready for `mimir_rules.content`.",
+			},
+			"rules": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "YAML content of the recording rule groups (`prometheusRules`), empty if the mixin defines none.",
+			},
+			"alerts": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "YAML content of the alerting rule groups (`prometheusAlerts`), empty if the mixin defines none.",
+			},
+			"group_names": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Names of the rule groups of `content`.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		}, /* End schema */
+	}
+}
+
+// mixinRuleGroups are the rule groups of a rendered mixin.
+type mixinRuleGroups struct {
+	Rules  *RuleGroups `yaml:"rules"`
+	Alerts *RuleGroups `yaml:"alerts"`
+}
+
+// renderMixin evaluates the mixin at path with the jsonnet library paths and the config
+// overrides, a JSON object.
+func renderMixin(path string, jpath []string, config string) (mixinRuleGroups, error) {
+	var groups mixinRuleGroups
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return groups, err
+	}
+	importPath, _ := json.Marshal(abs)
+	if config == "" {
+		config = "{}"
+	}
+
+	vm := jsonnet.MakeVM()
+	vm.Importer(&jsonnet.FileImporter{JPaths: jpath})
+	vm.ExtCode("config", config)
+	output, err := vm.EvaluateAnonymousSnippet(filepath.Join(filepath.Dir(abs), "mimir_mixin.jsonnet"), fmt.Sprintf(mixinSnippet, importPath))
+	if err != nil {
+		return groups, fmt.Errorf("failed to evaluate mixin %s: %v", path, err)
+	}
+
+	// The JSON output is valid YAML, decoded as the content of mimir_rules is
+	if err := yaml.Unmarshal([]byte(output), &groups); err != nil {
+		return groups, fmt.Errorf("unable to decode the rule groups of mixin %s: %v", path, err)
+	}
+	if groups.Rules == nil && groups.Alerts == nil {
+		return groups, fmt.Errorf("mixin %s defines neither prometheusRules nor prometheusAlerts", path)
+	}
+	return groups, nil
+}
+
+// marshalMixinRuleGroups returns the YAML content of groups, empty when it has none.
+func marshalMixinRuleGroups(groups *RuleGroups) (string, error) {
+	if groups == nil || len(groups.Groups) == 0 {
+		return "", nil
+	}
+	data, err := yaml.Marshal(groups)
+	return string(data), err
+}
+
+func dataSourcemimirMixinRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	path := d.Get("path").(string)
+	config := d.Get("config").(string)
+	var jpath []string
+	for _, p := range d.Get("jpath").([]interface{}) {
+		jpath = append(jpath, p.(string))
+	}
+
+	groups, err := renderMixin(path, jpath, config)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var all RuleGroups
+	for _, g := range []*RuleGroups{groups.Rules, groups.Alerts} {
+		if g != nil {
+			all.Groups = append(all.Groups, g.Groups...)
+		}
+	}
+	if err := validateRuleGroupsContent(all); err != nil {
+		return diag.FromErr(fmt.Errorf("mixin %s rendered invalid rule groups: %v", path, err))
+	}
+
+	content, err := marshalMixinRuleGroups(&all)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	rules, err := marshalMixinRuleGroups(groups.Rules)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	alerts, err := marshalMixinRuleGroups(groups.Alerts)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	groupNames, _ := ruleGroupsByName(all)
+
+	if err := d.Set("content", content); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("rules", rules); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("alerts", alerts); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("group_names", groupNames); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%x", sha256.Sum256([]byte(content))))
+
+	return nil
+}
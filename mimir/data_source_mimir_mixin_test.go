@@ -0,0 +1,151 @@
+package mimir
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/yaml.v3"
+)
+
+// writeFixtureMixin writes a mixin importing a library from a vendor directory, and returns
+// the path of the mixin and of the vendor directory.
+func writeFixtureMixin(t *testing.T) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"vendor/utils/utils.libsonnet": `{
+  severity(s):: { severity: s },
+}
+`,
+		"mixin/config.libsonnet": `{
+  _config+:: {
+    selector: 'job="api"',
+    forDuration: '5m',
+  },
+}
+`,
+		"mixin/mixin.libsonnet": `local utils = import 'utils/utils.libsonnet';
+(import 'config.libsonnet') + {
+  prometheusRules+:: {
+    groups+: [{
+      name: 'api.rules',
+      rules: [{
+        record: 'job:up:sum',
+        expr: 'sum by (job) (up{%s})' % $._config.selector,
+      }],
+    }],
+  },
+  prometheusAlerts+:: {
+    groups+: [{
+      name: 'api.alerts',
+      rules: [{
+        alert: 'APIDown',
+        expr: 'up{%s} == 0' % $._config.selector,
+        'for': $._config.forDuration,
+        labels: utils.severity('critical'),
+      }],
+    }],
+  },
+}
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return filepath.Join(dir, "mixin", "mixin.libsonnet"), filepath.Join(dir, "vendor")
+}
+
+func TestDataSourceMimirMixinRead(t *testing.T) {
+	path, vendor := writeFixtureMixin(t)
+	d := schema.TestResourceDataRaw(t, dataSourcemimirMixin().Schema, map[string]interface{}{
+		"path":   path,
+		"jpath":  []interface{}{vendor},
+		"config": `{"forDuration": "15m"}`,
+	})
+	if diags := dataSourcemimirMixinRead(context.Background(), d, nil); diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	if got := d.Get("group_names").([]interface{}); len(got) != 2 || got[0] != "api.rules" || got[1] != "api.alerts" {
+		t.Errorf("unexpected group names %v", got)
+	}
+	if rules := d.Get("rules").(string); !strings.Contains(rules, "api.rules") || strings.Contains(rules, "api.alerts") {
+		t.Errorf("expected only the recording rules in rules, got:\n%s", rules)
+	}
+	if alerts := d.Get("alerts").(string); !strings.Contains(alerts, "api.alerts") || strings.Contains(alerts, "api.rules") {
+		t.Errorf("expected only the alerting rules in alerts, got:\n%s", alerts)
+	}
+
+	var content RuleGroups
+	if err := yaml.Unmarshal([]byte(d.Get("content").(string)), &content); err != nil {
+		t.Fatal(err)
+	}
+	if len(content.Groups) != 2 {
+		t.Fatalf("expected 2 groups in content, got %+v", content.Groups)
+	}
+	alert := content.Groups[1].Rules[0]
+	if alert.For != "15m" {
+		t.Errorf("expected the config override to apply, got for %q", alert.For)
+	}
+	if alert.Expr != `up{job="api"} == 0` || alert.Labels["severity"] != "critical" {
+		t.Errorf("unexpected alert %+v", alert)
+	}
+
+	// The content is accepted as is by mimir_rules
+	rd := schema.TestResourceDataRaw(t, resourceMimirRules().Schema, map[string]interface{}{
+		namespaceKey: "api",
+		"content":    d.Get("content"),
+	})
+	if _, err := parseRuleGroupsConfiguration(rd); err != nil {
+		t.Errorf("mimir_rules rejected the content: %v", err)
+	}
+}
+
+func TestDataSourceMimirMixinReadErrors(t *testing.T) {
+	path, vendor := writeFixtureMixin(t)
+	empty := filepath.Join(t.TempDir(), "empty.libsonnet")
+	if err := os.WriteFile(empty, []byte("{ _config:: {} }\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		raw  map[string]interface{}
+		want string
+	}{
+		{
+			name: "missing jpath",
+			raw:  map[string]interface{}{"path": path},
+			want: "couldn't open import \"utils/utils.libsonnet\"",
+		},
+		{
+			name: "no rules",
+			raw:  map[string]interface{}{"path": empty},
+			want: "defines neither prometheusRules nor prometheusAlerts",
+		},
+		{
+			name: "invalid rule groups",
+			raw:  map[string]interface{}{"path": path, "jpath": []interface{}{vendor}, "config": `{"forDuration": "soon"}`},
+			want: "invalid 'for' duration 'soon'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, dataSourcemimirMixin().Schema, tt.raw)
+			diags := dataSourcemimirMixinRead(context.Background(), d, nil)
+			if !diags.HasError() || !strings.Contains(diags[0].Summary, tt.want) {
+				t.Errorf("expected an error containing %q, got %v", tt.want, diags)
+			}
+		})
+	}
+}
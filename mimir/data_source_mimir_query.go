@@ -0,0 +1,282 @@
+package mimir
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	apiQueryPath      = "/api/v1/query"
+	apiQueryRangePath = "/api/v1/query_range"
+)
+
+// promQueryResponse is the Prometheus HTTP API response of query and query_range.
+type promQueryResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+	Warnings []string `json:"warnings"`
+	Infos    []string `json:"infos"`
+}
+
+type promSeries struct {
+	Metric map[string]string `json:"metric"`
+	Value  []interface{}     `json:"value"`
+	Values [][]interface{}   `json:"values"`
+}
+
+func dataSourcemimirQuery() *schema.Resource {
+	return &schema.Resource{
+		Description: "Runs a PromQL instant or range query through the query-frontend (`query_frontend_uri`).",
+		ReadContext: dataSourcemimirQueryRead,
+
+		Schema: map[string]*schema.Schema{
+			orgIDKey: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: orgIDDescription,
+			},
+			"query": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "PromQL expression to evaluate.",
+				ValidateFunc: validatePromQLExpr,
+			},
+			"time": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "Evaluation time of an instant query, as a RFC3339 timestamp or a unix timestamp. Defaults to the current time.",
+				ConflictsWith: []string{"start"},
+				ValidateFunc:  validateQueryTime,
+			},
+			"start": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Start of a range query, as a RFC3339 timestamp or a unix timestamp. Setting it runs a range query.",
+				RequiredWith: []string{"end", "step"},
+				ValidateFunc: validateQueryTime,
+			},
+			"end": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "End of a range query, as a RFC3339 timestamp or a unix timestamp.",
+				RequiredWith: []string{"start"},
+				ValidateFunc: validateQueryTime,
+			},
+			"step": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Resolution step of a range query, e.g. `1m`.",
+				RequiredWith: []string{"start"},
+				ValidateFunc: validateDuration,
+			},
+			"timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Evaluation timeout, e.g. `30s`. Capped by the server-side timeout.",
+				ValidateFunc: validateDuration,
+			},
+			"result_type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Type of the result: `vector`, `matrix`, `scalar` or `string`.",
+			},
+			"result": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Query result. Instant queries set `value` and `timestamp`, range queries set `values`. A scalar or string result is a single entry without labels.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"labels": {
+							Type:     schema.TypeMap,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"timestamp": {
+							Type:     schema.TypeFloat,
+							Computed: true,
+						},
+						"value": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"values": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"timestamp": {
+										Type:     schema.TypeFloat,
+										Computed: true,
+									},
+									"value": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"warnings": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Warnings and infos returned with the result.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"request_headers": requestHeadersSchema("data source"),
+			"auth":            requestAuthSchema("data source"),
+		}, /* End schema */
+	}
+}
+
+func dataSourcemimirQueryRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).forResource(d)
+	orgID := d.Get(orgIDKey).(string)
+
+	headers := map[string]string{"Content-Type": "application/x-www-form-urlencoded"}
+	if orgID != "" {
+		headers["X-Scope-OrgID"] = orgID
+	}
+
+	params := url.Values{}
+	params.Set("query", d.Get("query").(string))
+	path := apiQueryPath
+	if start := d.Get("start").(string); start != "" {
+		path = apiQueryRangePath
+		params.Set("start", start)
+		params.Set("end", d.Get("end").(string))
+		params.Set("step", d.Get("step").(string))
+	} else if t := d.Get("time").(string); t != "" {
+		params.Set("time", t)
+	}
+	if timeout := d.Get("timeout").(string); timeout != "" {
+		params.Set("timeout", timeout)
+	}
+
+	// POST keeps long expressions out of the URL.
+	jobraw, err := client.sendRequest(ctx, "query_frontend", "POST", path, params.Encode(), headers)
+
+	baseMsg := "Cannot run query"
+	err = handleHTTPError(err, baseMsg)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var resp promQueryResponse
+	if err := json.Unmarshal([]byte(jobraw), &resp); err != nil {
+		return diag.FromErr(fmt.Errorf("unable to decode query response: %v", err))
+	}
+
+	result, err := flattenQueryResult(resp.Data.ResultType, resp.Data.Result)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%x", sha256.Sum256([]byte(orgID+"\n"+path+"?"+params.Encode()))))
+	if err := d.Set("result_type", resp.Data.ResultType); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("result", result); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("warnings", append(resp.Warnings, resp.Infos...)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func flattenQueryResult(resultType string, raw json.RawMessage) ([]interface{}, error) {
+	var result []interface{}
+	switch resultType {
+	case "vector", "matrix":
+		var series []promSeries
+		if err := json.Unmarshal(raw, &series); err != nil {
+			return nil, fmt.Errorf("unable to decode %s result: %v", resultType, err)
+		}
+		for _, s := range series {
+			entry := map[string]interface{}{"labels": s.Metric}
+			if s.Value != nil {
+				ts, value, err := flattenQuerySample(s.Value)
+				if err != nil {
+					return nil, err
+				}
+				entry["timestamp"] = ts
+				entry["value"] = value
+			}
+			var values []interface{}
+			for _, sample := range s.Values {
+				ts, value, err := flattenQuerySample(sample)
+				if err != nil {
+					return nil, err
+				}
+				values = append(values, map[string]interface{}{"timestamp": ts, "value": value})
+			}
+			entry["values"] = values
+			result = append(result, entry)
+		}
+	case "scalar", "string":
+		var sample []interface{}
+		if err := json.Unmarshal(raw, &sample); err != nil {
+			return nil, fmt.Errorf("unable to decode %s result: %v", resultType, err)
+		}
+		ts, value, err := flattenQuerySample(sample)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, map[string]interface{}{"timestamp": ts, "value": value})
+	default:
+		return nil, fmt.Errorf("unsupported query result type %q", resultType)
+	}
+	return result, nil
+}
+
+// flattenQuerySample decodes a [<unix time>, "<value>"] pair. Values stay strings so NaN
+// and Inf survive.
+func flattenQuerySample(sample []interface{}) (float64, string, error) {
+	if len(sample) != 2 {
+		return 0, "", fmt.Errorf("unexpected sample %v", sample)
+	}
+	ts, ok := sample[0].(float64)
+	if !ok {
+		return 0, "", fmt.Errorf("unexpected sample timestamp %v", sample[0])
+	}
+	value, ok := sample[1].(string)
+	if !ok {
+		return 0, "", fmt.Errorf("unexpected sample value %v", sample[1])
+	}
+	return ts, value, nil
+}
+
+func validateQueryTime(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if _, err := parseQueryTime(value); err != nil {
+		errors = append(errors, fmt.Errorf("\"%s\": %v", k, err))
+	}
+	return
+}
+
+// parseQueryTime parses a RFC3339 or unix timestamp, as accepted by the Prometheus HTTP API.
+func parseQueryTime(value string) (time.Time, error) {
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.UnixMilli(int64(math.Round(seconds * 1000))), nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither a RFC3339 nor a unix timestamp", value)
+	}
+	return t, nil
+}
@@ -0,0 +1,97 @@
+package mimir
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// queryTestHandler serves the query APIs of the query-frontend for tenant-1.
+func queryTestHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/prometheus"+apiQueryPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Scope-OrgID") != "tenant-1" || r.FormValue("time") != "1700000000" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"status":"error","error":"bad request"}`))
+			return
+		}
+		switch r.FormValue("query") {
+		case "up":
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[` +
+				`{"metric":{"__name__":"up","job":"api"},"value":[1700000000,"1"]}]},"warnings":["partial data"]}`))
+		case "scalar(up)":
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"scalar","result":[1700000000,"NaN"]}}`))
+		}
+	})
+	mux.HandleFunc("/prometheus"+apiQueryRangePath, func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("start") != "2023-11-14T22:00:00Z" || r.FormValue("step") != "1m" || r.FormValue("timeout") != "10s" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[` +
+			`{"metric":{"job":"api"},"values":[[1699999940,"1"],[1700000000,"0"]]}]}}`))
+	})
+	return mux
+}
+
+func TestDataSourceQueryRead_Instant(t *testing.T) {
+	client := newTestAPIClient(t, queryTestHandler())
+
+	d := readTestDataSource(t, dataSourcemimirQuery(), client, map[string]interface{}{orgIDKey: "tenant-1", "query": "up", "time": "1700000000"})
+	if d.Get("result_type") != "vector" || d.Get("result.#") != 1 {
+		t.Fatalf("unexpected result %v %v", d.Get("result_type"), d.Get("result"))
+	}
+	if d.Get("result.0.labels.job") != "api" || d.Get("result.0.value") != "1" || d.Get("result.0.timestamp") != float64(1700000000) {
+		t.Errorf("unexpected sample %v", d.Get("result.0"))
+	}
+	if d.Get("warnings.0") != "partial data" {
+		t.Errorf("unexpected warnings %v", d.Get("warnings"))
+	}
+
+	d = readTestDataSource(t, dataSourcemimirQuery(), client, map[string]interface{}{orgIDKey: "tenant-1", "query": "scalar(up)", "time": "1700000000"})
+	if d.Get("result_type") != "scalar" || d.Get("result.0.value") != "NaN" {
+		t.Errorf("unexpected scalar result %v", d.Get("result"))
+	}
+}
+
+func TestDataSourceQueryRead_Range(t *testing.T) {
+	client := newTestAPIClient(t, queryTestHandler())
+
+	d := readTestDataSource(t, dataSourcemimirQuery(), client, map[string]interface{}{
+		"query":   "up",
+		"start":   "2023-11-14T22:00:00Z",
+		"end":     "2023-11-14T22:13:20Z",
+		"step":    "1m",
+		"timeout": "10s",
+	})
+	if d.Get("result_type") != "matrix" || d.Get("result.0.values.#") != 2 {
+		t.Fatalf("unexpected result %v", d.Get("result"))
+	}
+	if d.Get("result.0.values.1.value") != "0" || d.Get("result.0.values.1.timestamp") != float64(1700000000) {
+		t.Errorf("unexpected samples %v", d.Get("result.0.values"))
+	}
+}
+
+func TestDataSourceQueryRead_Error(t *testing.T) {
+	client := newTestAPIClient(t, queryTestHandler())
+	d := schema.TestResourceDataRaw(t, dataSourcemimirQuery().Schema, map[string]interface{}{orgIDKey: "other", "query": "up", "time": "1700000000"})
+	if diags := dataSourcemimirQueryRead(context.Background(), d, client); !diags.HasError() {
+		t.Error("expected an error for a rejected query")
+	}
+}
+
+func TestValidateQueryTime(t *testing.T) {
+	for value, valid := range map[string]bool{
+		"1700000000":           true,
+		"1700000000.5":         true,
+		"2023-11-14T22:13:20Z": true,
+		"yesterday":            false,
+		"2023-11-14":           false,
+	} {
+		if _, errs := validateQueryTime(value, "time"); (len(errs) == 0) != valid {
+			t.Errorf("validateQueryTime(%q) = %v", value, errs)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package mimir
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/prometheus/common/model"
+)
+
+// ringEndpoint is the component and the path, at its root, serving the status page of a ring.
+type ringEndpoint struct {
+	component string
+	path      string
+}
+
+var ringEndpoints = map[string]ringEndpoint{
+	"alertmanager":  {"alertmanager", "/multitenant_alertmanager/ring"},
+	"compactor":     {"compactor", "/compactor/ring"},
+	"distributor":   {"distributor", "/distributor/ring"},
+	"ingester":      {"ingester", "/ingester/ring"},
+	"ruler":         {"ruler", "/ruler/ring"},
+	"store_gateway": {"store_gateway", "/store-gateway/ring"},
+}
+
+const ringStateActive = "ACTIVE"
+
+type ringStatus struct {
+	Shards []ringInstance `json:"shards"`
+	Now    time.Time      `json:"now"`
+}
+
+type ringInstance struct {
+	ID                  string    `json:"id"`
+	State               string    `json:"state"`
+	Address             string    `json:"address"`
+	Zone                string    `json:"zone"`
+	HeartbeatTimestamp  time.Time `json:"timestamp"`
+	RegisteredTimestamp time.Time `json:"registered_timestamp"`
+	ReadOnly            bool      `json:"read_only"`
+	Tokens              []uint32  `json:"tokens"`
+	NumTokens           int       `json:"num_tokens"`
+	Ownership           float64   `json:"ownership"`
+}
+
+func dataSourcemimirRingStatus() *schema.Resource {
+	return &schema.Resource{
+		Description: "Reads the status of the instances of a hash ring, e.g. to gate an apply on a healthy cluster.",
+		ReadContext: dataSourcemimirRingStatusRead,
+
+		Schema: map[string]*schema.Schema{
+			"ring": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Ring to read: `ingester`, `distributor`, `ruler`, `alertmanager`, `store_gateway` or `compactor`.",
+				ValidateFunc: validation.StringInSlice(sortedRingNames(), false),
+			},
+			"heartbeat_timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "1m",
+				Description:  "An instance whose last heartbeat is older than this is unhealthy.",
+				ValidateFunc: validateDuration,
+			},
+			"healthy": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether every instance of the ring is healthy. False for an empty ring.",
+			},
+			"instance_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of instances in the ring.",
+			},
+			"healthy_instance_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of `ACTIVE` instances with a recent heartbeat.",
+			},
+			"zones": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Zones of the instances, sorted.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"instances": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Instances of the ring, sorted by ID.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"state": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Ring state: `ACTIVE`, `JOINING`, `PENDING`, `LEAVING` or `LEFT`.",
+						},
+						"address": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"zone": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"heartbeat_timestamp": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Time of the last heartbeat, as a RFC3339 timestamp.",
+						},
+						"heartbeat_age_seconds": {
+							Type:        schema.TypeFloat,
+							Computed:    true,
+							Description: "Age of the last heartbeat, relative to the server time.",
+						},
+						"registered_timestamp": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Time the instance joined the ring, as a RFC3339 timestamp.",
+						},
+						"read_only": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"num_tokens": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"ownership": {
+							Type:        schema.TypeFloat,
+							Computed:    true,
+							Description: "Percentage of the ring owned by the instance, when reported.",
+						},
+						"healthy": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the instance is `ACTIVE` with a recent heartbeat.",
+						},
+					},
+				},
+			},
+			"request_headers": requestHeadersSchema("data source"),
+			"auth":            requestAuthSchema("data source"),
+		}, /* End schema */
+	}
+}
+
+func dataSourcemimirRingStatusRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).forResource(d)
+	ring := d.Get("ring").(string)
+	endpoint := ringEndpoints[ring]
+	heartbeatTimeout, _ := model.ParseDuration(d.Get("heartbeat_timeout").(string))
+
+	jobraw, err := client.sendRootRequest(ctx, endpoint.component, "GET", endpoint.path, "", map[string]string{"Accept": "application/json"})
+	baseMsg := fmt.Sprintf("Cannot read %s ring", ring)
+	err = handleHTTPError(err, baseMsg)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var status ringStatus
+	if err := json.Unmarshal([]byte(jobraw), &status); err != nil {
+		return diag.FromErr(fmt.Errorf("unable to decode %s ring status: %v", ring, err))
+	}
+	if status.Now.IsZero() {
+		status.Now = time.Now()
+	}
+
+	sort.Slice(status.Shards, func(i, j int) bool { return status.Shards[i].ID < status.Shards[j].ID })
+	instances := make([]interface{}, 0, len(status.Shards))
+	zones := make(map[string]bool)
+	healthyCount := 0
+	for _, instance := range status.Shards {
+		age := status.Now.Sub(instance.HeartbeatTimestamp)
+		healthy := instance.State == ringStateActive && age <= time.Duration(heartbeatTimeout)
+		if healthy {
+			healthyCount++
+		}
+		if instance.Zone != "" {
+			zones[instance.Zone] = true
+		}
+		numTokens := instance.NumTokens
+		if numTokens == 0 {
+			numTokens = len(instance.Tokens)
+		}
+		instances = append(instances, map[string]interface{}{
+			"id":                    instance.ID,
+			"state":                 instance.State,
+			"address":               instance.Address,
+			"zone":                  instance.Zone,
+			"heartbeat_timestamp":   instance.HeartbeatTimestamp.UTC().Format(time.RFC3339),
+			"heartbeat_age_seconds": age.Seconds(),
+			"registered_timestamp":  instance.RegisteredTimestamp.UTC().Format(time.RFC3339),
+			"read_only":             instance.ReadOnly,
+			"num_tokens":            numTokens,
+			"ownership":             instance.Ownership,
+			"healthy":               healthy,
+		})
+	}
+
+	d.SetId(ring)
+	for k, v := range map[string]interface{}{
+		"healthy":                len(instances) > 0 && healthyCount == len(instances),
+		"instance_count":         len(instances),
+		"healthy_instance_count": healthyCount,
+		"zones":                  sortedSet(zones),
+		"instances":              instances,
+	} {
+		if err := d.Set(k, v); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+func sortedRingNames() []string {
+	names := make([]string, 0, len(ringEndpoints))
+	for name := range ringEndpoints {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedSet(set map[string]bool) []string {
+	values := make([]string, 0, len(set))
+	for v := range set {
+		values = append(values, v)
+	}
+	sort.Strings(values)
+	return values
+}
@@ -0,0 +1,101 @@
+package mimir
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestDataSourceRingStatusRead(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/store-gateway/ring" || r.Header.Get("Accept") != "application/json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"now":"2024-05-01T12:00:00Z","shards":[
+			{"id":"store-gateway-zone-b-0","state":"ACTIVE","address":"10.0.0.2:9095","zone":"zone-b","timestamp":"2024-05-01T11:58:00Z","registered_timestamp":"2024-04-01T00:00:00Z","tokens":[1,2,3]},
+			{"id":"store-gateway-zone-a-0","state":"ACTIVE","address":"10.0.0.1:9095","zone":"zone-a","timestamp":"2024-05-01T11:59:50Z","registered_timestamp":"2024-04-01T00:00:00Z","tokens":[4,5]},
+			{"id":"store-gateway-zone-a-1","state":"LEAVING","address":"10.0.0.3:9095","zone":"zone-a","timestamp":"2024-05-01T11:59:55Z","registered_timestamp":"2024-04-01T00:00:00Z"}
+		]}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewAPIClient(&apiClientOpt{uri: "http://127.0.0.1:1", storeGatewayURI: srv.URL, headers: map[string]string{}, timeout: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := schema.TestResourceDataRaw(t, dataSourcemimirRingStatus().Schema, map[string]interface{}{"ring": "store_gateway"})
+	if diags := dataSourcemimirRingStatusRead(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+
+	if d.Id() != "store_gateway" || d.Get("healthy").(bool) || d.Get("instance_count") != 3 || d.Get("healthy_instance_count") != 1 {
+		t.Errorf("unexpected ring status: %v", d.State())
+	}
+	if zones := d.Get("zones").([]interface{}); len(zones) != 2 || zones[0] != "zone-a" || zones[1] != "zone-b" {
+		t.Errorf("unexpected zones %v", zones)
+	}
+	instances := d.Get("instances").([]interface{})
+	first := instances[0].(map[string]interface{})
+	if first["id"] != "store-gateway-zone-a-0" || first["healthy"] != true || first["heartbeat_age_seconds"] != 10.0 || first["num_tokens"] != 2 {
+		t.Errorf("unexpected first instance %v", first)
+	}
+	// Stale heartbeat.
+	if last := instances[2].(map[string]interface{}); last["id"] != "store-gateway-zone-b-0" || last["healthy"] != false || last["heartbeat_age_seconds"] != 120.0 {
+		t.Errorf("unexpected last instance %v", last)
+	}
+
+	d = schema.TestResourceDataRaw(t, dataSourcemimirRingStatus().Schema, map[string]interface{}{"ring": "store_gateway", "heartbeat_timeout": "5m"})
+	if diags := dataSourcemimirRingStatusRead(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+	if d.Get("healthy_instance_count") != 2 {
+		t.Errorf("expected 2 healthy instances with a 5m heartbeat timeout, got %v", d.Get("healthy_instance_count"))
+	}
+}
+
+func TestDataSourceRingStatusReadComponentURIs(t *testing.T) {
+	// Each component serves its own ring only, at the root of its own URI, which is
+	// configured with its documented prefix.
+	servers := map[string]string{}
+	for ring, endpoint := range ringEndpoints {
+		path := endpoint.path
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != path {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(`{"now":"2024-05-01T12:00:00Z","shards":[{"id":"0","state":"ACTIVE","timestamp":"2024-05-01T12:00:00Z"}]}`))
+		}))
+		t.Cleanup(srv.Close)
+		servers[ring] = srv.URL
+	}
+
+	client, err := NewAPIClient(&apiClientOpt{
+		uri:             "http://127.0.0.1:1",
+		alertmanagerURI: servers["alertmanager"],
+		compactorURI:    servers["compactor"],
+		distributorURI:  servers["distributor"] + "/distributor",
+		ingesterURI:     servers["ingester"],
+		rulerURI:        servers["ruler"] + "/prometheus",
+		storeGatewayURI: servers["store_gateway"],
+		headers:         map[string]string{},
+		timeout:         2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for ring := range ringEndpoints {
+		d := schema.TestResourceDataRaw(t, dataSourcemimirRingStatus().Schema, map[string]interface{}{"ring": ring})
+		if diags := dataSourcemimirRingStatusRead(context.Background(), d, client); diags.HasError() {
+			t.Errorf("%s: %v", ring, diags)
+			continue
+		}
+		if d.Get("instance_count") != 1 {
+			t.Errorf("%s: unexpected ring status: %v", ring, d.State())
+		}
+	}
+}
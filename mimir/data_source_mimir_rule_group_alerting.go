@@ -78,13 +78,15 @@ func dataSourcemimirRuleGroupAlerting() *schema.Resource {
 					},
 				},
 			},
+			"request_headers": requestHeadersSchema("data source"),
+			"auth":            requestAuthSchema("data source"),
 		}, /* End schema */
 
 	}
 }
 
 func dataSourcemimirRuleGroupAlertingRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*apiClient)
+	client := meta.(*apiClient).forResource(d)
 	name := d.Get("name").(string)
 	namespace := d.Get("namespace").(string)
 	orgID := d.Get(orgIDKey).(string)
@@ -96,7 +98,7 @@ func dataSourcemimirRuleGroupAlertingRead(ctx context.Context, d *schema.Resourc
 		headers["X-Scope-OrgID"] = orgID
 	}
 	path := rulesGroupPath(namespace, name)
-	jobraw, err := client.sendRequest("ruler", "GET", path, "", headers)
+	jobraw, err := client.sendRequest(ctx, "ruler", "GET", path, "", headers)
 
 	baseMsg := fmt.Sprintf("Cannot read alerting rule group '%s' -", name)
 	err = handleHTTPError(err, baseMsg)
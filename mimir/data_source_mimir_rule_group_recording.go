@@ -81,13 +81,15 @@ func dataSourcemimirRuleGroupRecording() *schema.Resource {
 					},
 				},
 			},
+			"request_headers": requestHeadersSchema("data source"),
+			"auth":            requestAuthSchema("data source"),
 		}, /* End schema */
 
 	}
 }
 
 func dataSourcemimirRuleGroupRecordingRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*apiClient)
+	client := meta.(*apiClient).forResource(d)
 	name := d.Get("name").(string)
 	namespace := d.Get("namespace").(string)
 	orgID := d.Get(orgIDKey).(string)
@@ -99,7 +101,7 @@ func dataSourcemimirRuleGroupRecordingRead(ctx context.Context, d *schema.Resour
 		headers["X-Scope-OrgID"] = orgID
 	}
 	path := rulesGroupPath(namespace, name)
-	jobraw, err := client.sendRequest("ruler", "GET", path, "", headers)
+	jobraw, err := client.sendRequest(ctx, "ruler", "GET", path, "", headers)
 
 	baseMsg := fmt.Sprintf("Cannot read recording rule group '%s' -", name)
 	err = handleHTTPError(err, baseMsg)
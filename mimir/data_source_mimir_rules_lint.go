@@ -0,0 +1,128 @@
+package mimir
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/yaml.v3"
+)
+
+func dataSourcemimirRulesLint() *schema.Resource {
+	return &schema.Resource{
+		Description: "Evaluates rule_lint policies and the provider promql_checks on rule groups YAML content, without calling the Mimir API.",
+		ReadContext: dataSourcemimirRulesLintRead,
+
+		Schema: map[string]*schema.Schema{
+			namespaceKey: {
+				Type:         schema.TypeString,
+				Description:  "Namespace the content is linted for, used to select the policies that apply.",
+				Optional:     true,
+				Default:      defaultNamespace,
+				ValidateFunc: validateNamespace,
+			},
+			"content": {
+				Type:        schema.TypeString,
+				Description: "YAML content containing rule groups, in the format of `mimir_rules`.",
+				Required:    true,
+			},
+			"policy": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Policies to evaluate. If not set, the provider `rule_lint` policies are used.",
+				Elem:        ruleLintPolicySchema(),
+			},
+			"findings": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Policy violations found in the content.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"policy": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"severity": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"group": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"rule": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"message": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"error_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of error-level findings.",
+			},
+			"warning_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of warning-level findings.",
+			},
+		}, /* End schema */
+	}
+}
+
+func dataSourcemimirRulesLintRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	namespace := d.Get(namespaceKey).(string)
+	content := d.Get("content").(string)
+
+	var ruleGroups RuleGroups
+	if err := yaml.Unmarshal([]byte(content), &ruleGroups); err != nil {
+		return diag.FromErr(fmt.Errorf("unable to decode rule groups content: %v", err))
+	}
+
+	policies := ruleLintPolicies
+	if v, ok := d.GetOk("policy"); ok {
+		var err error
+		policies, err = expandRuleLintPolicies(v.([]interface{}))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	var findings []map[string]interface{}
+	var errorCount, warningCount int
+	for _, f := range ruleLintFindings(namespace, ruleGroups, policies) {
+		if f.Severity == ruleLintSeverityError {
+			errorCount++
+		} else {
+			warningCount++
+		}
+		findings = append(findings, map[string]interface{}{
+			"policy":   f.Policy,
+			"severity": f.Severity,
+			"group":    f.Group,
+			"rule":     f.Rule,
+			"message":  f.Message,
+		})
+	}
+
+	if err := d.Set("findings", findings); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("error_count", errorCount); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("warning_count", warningCount); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%x", sha256.Sum256([]byte(namespace+"\n"+content))))
+
+	return nil
+}
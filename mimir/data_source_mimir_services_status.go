@@ -0,0 +1,132 @@
+package mimir
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	apiServicesPath = "/services"
+
+	serviceStateRunning = "Running"
+)
+
+type serviceStatus struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+func dataSourcemimirServicesStatus() *schema.Resource {
+	return &schema.Resource{
+		Description: "Reads the status of the internal services of a Mimir process (`/services`).",
+		ReadContext: dataSourcemimirServicesStatusRead,
+
+		Schema: map[string]*schema.Schema{
+			"component": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Component to read the services of: `ruler`, `alertmanager`, `distributor`, `ingester`, `store_gateway` or `compactor`, using the matching provider URI without its `/prometheus` or `/distributor` prefix. Defaults to the provider `uri`.",
+				ValidateFunc: validation.StringInSlice([]string{"ruler", "alertmanager", "distributor", "ingester", "store_gateway", "compactor"}, false),
+			},
+			"running": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether every service is `Running`.",
+			},
+			"not_running": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Names of the services that are not `Running`, sorted.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"services": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Services of the process, sorted by name.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Service state: `New`, `Starting`, `Running`, `Stopping`, `Terminated` or `Failed`.",
+						},
+					},
+				},
+			},
+			"request_headers": requestHeadersSchema("data source"),
+			"auth":            requestAuthSchema("data source"),
+		}, /* End schema */
+	}
+}
+
+func dataSourcemimirServicesStatusRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).forResource(d)
+	component := d.Get("component").(string)
+
+	jobraw, err := client.sendRootRequest(ctx, component, "GET", apiServicesPath, "", map[string]string{"Accept": "application/json"})
+	baseMsg := "Cannot read services status"
+	err = handleHTTPError(err, baseMsg)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	statuses, err := decodeServicesStatus(jobraw)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+
+	services := make([]interface{}, 0, len(statuses))
+	notRunning := make([]string, 0)
+	for _, s := range statuses {
+		services = append(services, map[string]interface{}{
+			"name":   s.Name,
+			"status": s.Status,
+		})
+		if s.Status != serviceStateRunning {
+			notRunning = append(notRunning, s.Name)
+		}
+	}
+
+	if component == "" {
+		d.SetId("default")
+	} else {
+		d.SetId(component)
+	}
+	if err := d.Set("running", len(services) > 0 && len(notRunning) == 0); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("not_running", notRunning); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("services", services); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// decodeServicesStatus accepts both a list of services and an object wrapping it.
+func decodeServicesStatus(body string) ([]serviceStatus, error) {
+	var statuses []serviceStatus
+	if err := json.Unmarshal([]byte(body), &statuses); err == nil {
+		return statuses, nil
+	}
+	var wrapped struct {
+		Services []serviceStatus `json:"services"`
+	}
+	if err := json.Unmarshal([]byte(body), &wrapped); err != nil {
+		return nil, fmt.Errorf("unable to decode services status: %v", err)
+	}
+	return wrapped.Services, nil
+}
@@ -0,0 +1,47 @@
+package mimir
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestDataSourceServicesStatusRead(t *testing.T) {
+	for _, body := range []string{
+		`[{"name":"ruler","status":"Running"},{"name":"alertmanager","status":"Starting"},{"name":"server","status":"Running"}]`,
+		`{"services":[{"name":"ruler","status":"Running"},{"name":"alertmanager","status":"Starting"},{"name":"server","status":"Running"}]}`,
+	} {
+		client := newTestAPIClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != apiServicesPath || r.Header.Get("Accept") != "application/json" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(body))
+		}))
+		d := readTestDataSource(t, dataSourcemimirServicesStatus(), client, map[string]interface{}{})
+
+		notRunning := d.Get("not_running").([]interface{})
+		services := d.Get("services").([]interface{})
+		if d.Get("running").(bool) || len(notRunning) != 1 || notRunning[0] != "alertmanager" ||
+			len(services) != 3 || services[0].(map[string]interface{})["name"] != "alertmanager" {
+			t.Errorf("unexpected services status: %v", d.State())
+		}
+	}
+}
+
+func TestDataSourceServicesStatusReadComponentURIs(t *testing.T) {
+	// The services page is served at the root of the components, whose urls have the
+	// /prometheus and /distributor prefixes.
+	client := newTestAPIClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != apiServicesPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`[{"name":"server","status":"Running"}]`))
+	}))
+	for _, component := range []string{"ruler", "distributor", ""} {
+		d := readTestDataSource(t, dataSourcemimirServicesStatus(), client, map[string]interface{}{"component": component})
+		if !d.Get("running").(bool) {
+			t.Errorf("%q: unexpected services status: %v", component, d.State())
+		}
+	}
+}
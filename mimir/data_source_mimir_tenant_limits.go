@@ -0,0 +1,220 @@
+package mimir
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// apiUserLimitsPath is served at the root, not under the distributor prefix.
+const apiUserLimitsPath = "/api/v1/user_limits"
+
+type tenantLimits struct {
+	IngestionRate               float64 `json:"ingestion_rate"`
+	IngestionBurstSize          int     `json:"ingestion_burst_size"`
+	MaxGlobalSeriesPerUser      int     `json:"max_global_series_per_user"`
+	MaxGlobalSeriesPerMetric    int     `json:"max_global_series_per_metric"`
+	MaxGlobalExemplarsPerUser   int     `json:"max_global_exemplars_per_user"`
+	RulerMaxRulesPerRuleGroup   int     `json:"ruler_max_rules_per_rule_group"`
+	RulerMaxRuleGroupsPerTenant int     `json:"ruler_max_rule_groups_per_tenant"`
+	// all holds every limit returned, including the ones without a dedicated attribute.
+	all map[string]string
+}
+
+func dataSourcemimirTenantLimits() *schema.Resource {
+	return &schema.Resource{
+		Description: "Reads the effective limits of a tenant (`/api/v1/user_limits`).",
+		ReadContext: dataSourcemimirTenantLimitsRead,
+
+		Schema: map[string]*schema.Schema{
+			orgIDKey: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: orgIDDescription,
+			},
+			"ingestion_rate": {
+				Type:        schema.TypeFloat,
+				Computed:    true,
+				Description: "Per-tenant ingestion rate limit, in samples per second.",
+			},
+			"ingestion_burst_size": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Per-tenant allowed ingestion burst size, in samples.",
+			},
+			"max_global_series_per_user": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Maximum number of in-memory series of the tenant, across the cluster. 0 means unlimited.",
+			},
+			"max_global_series_per_metric": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Maximum number of in-memory series per metric name, across the cluster. 0 means unlimited.",
+			},
+			"max_global_exemplars_per_user": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Maximum number of exemplars in memory, across the cluster. 0 disables exemplars storage.",
+			},
+			"ruler_max_rules_per_rule_group": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Maximum number of rules per rule group. 0 means unlimited.",
+			},
+			"ruler_max_rule_groups_per_tenant": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Maximum number of rule groups of the tenant. 0 means unlimited.",
+			},
+			"limits": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "Every limit returned by Mimir, as strings.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"request_headers": requestHeadersSchema("data source"),
+			"auth":            requestAuthSchema("data source"),
+		}, /* End schema */
+	}
+}
+
+func dataSourcemimirTenantLimitsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).forResource(d)
+	orgID := d.Get(orgIDKey).(string)
+	if orgID == "" {
+		orgID = client.headers["X-Scope-OrgID"]
+	}
+
+	limits, err := client.fetchTenantLimits(ctx, orgID)
+	baseMsg := fmt.Sprintf("Cannot read limits of tenant '%s' -", orgID)
+	err = handleHTTPError(err, baseMsg)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(orgID)
+	for k, v := range map[string]interface{}{
+		"ingestion_rate":                   limits.IngestionRate,
+		"ingestion_burst_size":             limits.IngestionBurstSize,
+		"max_global_series_per_user":       limits.MaxGlobalSeriesPerUser,
+		"max_global_series_per_metric":     limits.MaxGlobalSeriesPerMetric,
+		"max_global_exemplars_per_user":    limits.MaxGlobalExemplarsPerUser,
+		"ruler_max_rules_per_rule_group":   limits.RulerMaxRulesPerRuleGroup,
+		"ruler_max_rule_groups_per_tenant": limits.RulerMaxRuleGroupsPerTenant,
+		"limits":                           limits.all,
+	} {
+		if err := d.Set(k, v); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+func (client *apiClient) fetchTenantLimits(ctx context.Context, orgID string) (*tenantLimits, error) {
+	headers := make(map[string]string)
+	if orgID != "" {
+		headers["X-Scope-OrgID"] = orgID
+	}
+	jobraw, err := client.sendRequest(ctx, "", "GET", apiUserLimitsPath, "", headers)
+	if err != nil {
+		return nil, err
+	}
+
+	var limits tenantLimits
+	if err := json.Unmarshal([]byte(jobraw), &limits); err != nil {
+		return nil, fmt.Errorf("unable to decode tenant limits: %v", err)
+	}
+	var all map[string]interface{}
+	if err := json.Unmarshal([]byte(jobraw), &all); err != nil {
+		return nil, fmt.Errorf("unable to decode tenant limits: %v", err)
+	}
+	limits.all = make(map[string]string, len(all))
+	for k, v := range all {
+		switch value := v.(type) {
+		case string:
+			limits.all[k] = value
+		case float64:
+			limits.all[k] = strconv.FormatFloat(value, 'f', -1, 64)
+		default:
+			b, _ := json.Marshal(value)
+			limits.all[k] = string(b)
+		}
+	}
+	return &limits, nil
+}
+
+// tenantLimitsFindings returns the rule groups of namespace exceeding the tenant ruler
+// limits once applied: managed, and previously managed ones, whose groups are replaced,
+// the groups of the tenant in the other namespaces are kept. The limits are best effort:
+// when they cannot be read, nothing is reported.
+func tenantLimitsFindings(ctx context.Context, client *apiClient, orgID, namespace string, ruleGroups RuleGroups, previouslyManaged []string) []string {
+	limits, err := client.fetchTenantLimits(ctx, orgID)
+	if err != nil {
+		tflog.Debug(ctx, "Unable to read the tenant limits, skipping the limits check", map[string]interface{}{
+			"org_id": orgID,
+			"error":  err.Error(),
+		})
+		return nil
+	}
+
+	var findings []string
+	if limit := limits.RulerMaxRuleGroupsPerTenant; limit > 0 {
+		kept, err := keptRuleGroupsCount(ctx, client, orgID, namespace, ruleGroups, previouslyManaged)
+		if err != nil {
+			tflog.Debug(ctx, "Unable to list the rule groups of the tenant, only counting the managed ones", map[string]interface{}{
+				"org_id": orgID,
+				"error":  err.Error(),
+			})
+		}
+		if total := kept + len(ruleGroups.Groups); total > limit {
+			findings = append(findings, fmt.Sprintf("the tenant would have %d rule groups (%d managed here, %d others), more than the tenant limit ruler_max_rule_groups_per_tenant (%d)", total, len(ruleGroups.Groups), kept, limit))
+		}
+	}
+	if limit := limits.RulerMaxRulesPerRuleGroup; limit > 0 {
+		for _, group := range ruleGroups.Groups {
+			if len(group.Rules) > limit {
+				findings = append(findings, fmt.Sprintf("group '%s' has %d rules, more than the tenant limit ruler_max_rules_per_rule_group (%d)", group.Name, len(group.Rules), limit))
+			}
+		}
+	}
+	return findings
+}
+
+// keptRuleGroupsCount returns the number of rule groups of the tenant an apply of the
+// managed ruleGroups of namespace leaves as is.
+func keptRuleGroupsCount(ctx context.Context, client *apiClient, orgID, namespace string, ruleGroups RuleGroups, previouslyManaged []string) (int, error) {
+	namespaces, err := generateReadRuleNamespaces(ctx, client, orgID)
+	if err != nil {
+		return 0, err
+	}
+	replaced := make(map[string]bool, len(ruleGroups.Groups)+len(previouslyManaged))
+	for _, group := range ruleGroups.Groups {
+		replaced[group.Name] = true
+	}
+	for _, name := range previouslyManaged {
+		replaced[name] = true
+	}
+
+	kept := 0
+	for ns, groups := range namespaces {
+		for _, node := range groups {
+			var group struct {
+				Name string `yaml:"name"`
+			}
+			if err := node.Decode(&group); err != nil {
+				return 0, fmt.Errorf("unable to decode a rule group of namespace '%s': %v", ns, err)
+			}
+			if ns != namespace || !replaced[group.Name] {
+				kept++
+			}
+		}
+	}
+	return kept, nil
+}
@@ -0,0 +1,108 @@
+package mimir
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// tenantLimitsTestHandler serves the limits of tenant-1.
+func tenantLimitsTestHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != apiUserLimitsPath || r.Header.Get("X-Scope-OrgID") != "tenant-1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"ingestion_rate":10000.5,"ingestion_burst_size":200000,"max_global_series_per_user":150000,` +
+			`"max_global_series_per_metric":0,"max_global_exemplars_per_user":0,"ruler_max_rules_per_rule_group":2,` +
+			`"ruler_max_rule_groups_per_tenant":1,"compactor_blocks_retention_period":"1y"}`))
+	})
+}
+
+func TestDataSourceTenantLimitsRead(t *testing.T) {
+	client := newTestAPIClient(t, tenantLimitsTestHandler())
+	d := readTestDataSource(t, dataSourcemimirTenantLimits(), client, map[string]interface{}{})
+	if d.Id() != "tenant-1" || d.Get("ingestion_rate") != 10000.5 || d.Get("max_global_series_per_user") != 150000 || d.Get("ruler_max_rule_groups_per_tenant") != 1 {
+		t.Errorf("unexpected limits: %v", d.State())
+	}
+	if d.Get("limits.compactor_blocks_retention_period") != "1y" || d.Get("limits.ingestion_burst_size") != "200000" {
+		t.Errorf("unexpected raw limits %v", d.Get("limits"))
+	}
+
+	d = schema.TestResourceDataRaw(t, dataSourcemimirTenantLimits().Schema, map[string]interface{}{orgIDKey: "other"})
+	if diags := dataSourcemimirTenantLimitsRead(context.Background(), d, client); !diags.HasError() {
+		t.Error("expected an error for an unknown tenant")
+	}
+}
+
+func TestTenantLimitsFindings(t *testing.T) {
+	limits := tenantLimitsTestHandler()
+	client := newTestAPIClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/prometheus"+rulesListPath() {
+			_, _ = w.Write([]byte("default:\n  - name: old\n    rules: []\n  - name: unmanaged\n    rules: []\nother:\n  - name: o1\n    rules: []\n"))
+			return
+		}
+		limits.ServeHTTP(w, r)
+	}))
+	ruleGroups := RuleGroups{Groups: []RuleGroup{
+		{Name: "small", Rules: []Rule{{Record: "a", Expr: "up"}}},
+		{Name: "big", Rules: []Rule{{Record: "a", Expr: "up"}, {Record: "b", Expr: "up"}, {Record: "c", Expr: "up"}}},
+	}}
+
+	// The previously managed group 'old' is replaced, 'unmanaged' and 'o1' are kept.
+	findings := tenantLimitsFindings(context.Background(), client, "tenant-1", "default", ruleGroups, []string{"old"})
+	if len(findings) != 2 ||
+		!strings.Contains(findings[0], "would have 4 rule groups (2 managed here, 2 others)") ||
+		!strings.Contains(findings[1], "group 'big' has 3 rules") {
+		t.Errorf("unexpected findings %q", findings)
+	}
+
+	// Limits that cannot be read are not reported.
+	if findings := tenantLimitsFindings(context.Background(), client, "other", "default", ruleGroups, nil); len(findings) != 0 {
+		t.Errorf("unexpected findings for an unknown tenant %q", findings)
+	}
+}
+
+// TestResourceMimirRulesTenantLimits checks the limits are read at plan, with the
+// request_headers of the resource, counting the rule groups of the tenant in the other
+// namespaces.
+func TestResourceMimirRulesTenantLimits(t *testing.T) {
+	var limitsRequests int
+	client := newTestAPIClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Header.Get("X-Team") != "a":
+			w.WriteHeader(http.StatusForbidden)
+		case r.URL.Path == apiUserLimitsPath:
+			limitsRequests++
+			_, _ = w.Write([]byte(`{"ruler_max_rule_groups_per_tenant":2}`))
+		case r.URL.Path == "/prometheus"+rulesListPath():
+			_, _ = w.Write([]byte("other:\n  - name: o1\n    rules: []\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	content := "groups:\n  - name: g1\n    rules:\n      - record: a\n        expr: up\n"
+	raw := map[string]interface{}{
+		namespaceKey:      "default",
+		"content":         content,
+		"request_headers": map[string]interface{}{"X-Team": "a"},
+	}
+	r := resourceMimirRules()
+	if _, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(raw), client); err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+
+	raw["content"] = content + "  - name: g2\n    rules:\n      - record: b\n        expr: up\n"
+	_, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(raw), client)
+	if err == nil || !strings.Contains(err.Error(), "would have 3 rule groups (2 managed here, 1 others)") {
+		t.Errorf("expected a tenant limits error, got %v", err)
+	}
+	if limitsRequests == 0 {
+		t.Error("expected the limits to be read at plan")
+	}
+}
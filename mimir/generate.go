@@ -0,0 +1,502 @@
+package mimir
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	generateRulesModeTyped = "typed"
+	generateRulesModeRules = "rules"
+)
+
+var (
+	tfIdentifierRegexp            = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_-]*$`)
+	tfIdentifierInvalidCharRegexp = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+)
+
+// hclExpr is a value rendered as a raw HCL expression instead of a quoted string.
+type hclExpr string
+
+// generatedFile is a file emitted by the generate command, relative to the output directory.
+type generatedFile struct {
+	path    string
+	content []byte
+}
+
+// RunGenerate implements the `generate` command of the provider binary: it walks the ruler
+// and alertmanager APIs of a tenant and writes import blocks plus matching resource HCL, so
+// an existing tenant can be brought under Terraform management without hand-written config.
+func RunGenerate(args []string) error {
+	fs := flag.NewFlagSet("generate", flag.ContinueOnError)
+
+	orgID := fs.String("org-id", os.Getenv("MIMIR_ORG_ID"), "organization id (X-Scope-OrgID) to export")
+	outDir := fs.String("out", ".", "directory where the generated files are written")
+	rulesMode := fs.String("rules-resource", generateRulesModeTyped, "resource used for rule groups: 'typed' (mimir_rule_group_alerting/recording) or 'rules' (one mimir_rules per namespace)")
+	opt := &apiClientOpt{headers: make(map[string]string)}
+	fs.StringVar(&opt.uri, "uri", os.Getenv("MIMIR_URI"), "mimir base url")
+	fs.StringVar(&opt.rulerURI, "ruler-uri", os.Getenv("MIMIR_RULER_URI"), "mimir ruler base url")
+	fs.StringVar(&opt.alertmanagerURI, "alertmanager-uri", os.Getenv("MIMIR_ALERTMANAGER_URI"), "mimir alertmanager base url")
+	fs.StringVar(&opt.token, "token", os.Getenv("MIMIR_TOKEN"), "token for Bearer auth to the API")
+	fs.StringVar(&opt.username, "username", os.Getenv("MIMIR_USERNAME"), "username for BASIC auth to the API")
+	fs.StringVar(&opt.password, "password", os.Getenv("MIMIR_PASSWORD"), "password for BASIC auth to the API")
+	fs.StringVar(&opt.cert, "cert", os.Getenv("MIMIR_TLS_CERT"), "client cert (filepath or inline) for TLS client authentication")
+	fs.StringVar(&opt.key, "key", os.Getenv("MIMIR_TLS_KEY"), "client key (filepath or inline) for TLS client authentication")
+	fs.StringVar(&opt.ca, "ca", os.Getenv("MIMIR_TLS_CA"), "client ca (filepath or inline) for TLS client authentication")
+	fs.BoolVar(&opt.insecure, "insecure", false, "disable TLS verification of the host")
+	fs.IntVar(&opt.timeout, "timeout", 60, "request timeout in seconds")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *orgID == "" {
+		return fmt.Errorf("-org-id (or MIMIR_ORG_ID) is required")
+	}
+	if !validOrgID(*orgID) {
+		return fmt.Errorf("invalid org id %q: must not be \".\"/\"..\" and must contain no control characters or '/'", *orgID)
+	}
+	opt.headers["X-Scope-OrgID"] = *orgID
+
+	client, err := NewAPIClient(opt)
+	if err != nil {
+		return err
+	}
+
+	// Abort the in-flight requests on Ctrl-C.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	files, err := generateConfig(ctx, client, *orgID, *rulesMode)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		path := filepath.Join(*outDir, f.path)
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, f.content, 0600); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stdout, "wrote %s\n", path)
+	}
+	return nil
+}
+
+// generateConfig reads the tenant rule groups and alertmanager config and renders them as
+// Terraform files: imports.tf, rules.tf, alertmanager.tf and, for mimir_rules, one YAML
+// content file per namespace under rules/.
+func generateConfig(ctx context.Context, client *apiClient, orgID, rulesMode string) ([]generatedFile, error) {
+	if rulesMode != generateRulesModeTyped && rulesMode != generateRulesModeRules {
+		return nil, fmt.Errorf("invalid rules resource mode %q: must be %q or %q", rulesMode, generateRulesModeTyped, generateRulesModeRules)
+	}
+
+	var imports, rules, alertmanager strings.Builder
+	var files []generatedFile
+	names := make(map[string]bool)
+
+	namespaces, err := generateReadRuleNamespaces(ctx, client, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	nsNames := make([]string, 0, len(namespaces))
+	for ns := range namespaces {
+		nsNames = append(nsNames, ns)
+	}
+	sort.Strings(nsNames)
+
+	for _, ns := range nsNames {
+		var mixed []yaml.Node
+		var mixedNames []string
+		for _, node := range namespaces[ns] {
+			var group RuleGroup
+			if err := node.Decode(&group); err != nil {
+				return nil, fmt.Errorf("unable to decode rule group in namespace '%s': %v", ns, err)
+			}
+
+			kind, ok := ruleGroupKind(group)
+			if rulesMode == generateRulesModeRules || !ok {
+				// A group mixing alerting and recording rules has no typed resource.
+				mixed = append(mixed, node)
+				mixedNames = append(mixedNames, group.Name)
+				continue
+			}
+
+			resourceType := "mimir_rule_group_" + kind
+			resName := uniqueResourceName(names, resourceType, ns+"_"+group.Name)
+			var res *schema.Resource
+			data := map[string]interface{}{
+				orgIDKey:         orgID,
+				namespaceKey:     ns,
+				"name":           group.Name,
+				intervalKey:      group.Interval,
+				"source_tenants": group.SourceTenants,
+				labelsKey:        group.Labels,
+			}
+			if kind == "alerting" {
+				var g alertingRuleGroup
+				if err := node.Decode(&g); err != nil {
+					return nil, fmt.Errorf("unable to decode alerting rule group '%s' (namespace: %s): %v", group.Name, ns, err)
+				}
+				res = resourcemimirRuleGroupAlerting()
+				data["rule"] = flattenAlertingRules(g.Rules)
+			} else {
+				var g recordingRuleGroup
+				if err := node.Decode(&g); err != nil {
+					return nil, fmt.Errorf("unable to decode recording rule group '%s' (namespace: %s): %v", group.Name, ns, err)
+				}
+				res = resourcemimirRuleGroupRecording()
+				data["query_offset"] = g.QueryOffset
+				data["evaluation_delay"] = g.EvaluationDelay
+				data["rule"] = flattenRecordingRules(g.Rules)
+			}
+
+			writeImportBlock(&imports, resourceType, resName, buildRuleGroupID(orgID, ns, group.Name))
+			writeResourceBlock(&rules, resourceType, resName, res.Schema, data)
+		}
+
+		if len(mixed) == 0 {
+			continue
+		}
+
+		// Keep the server YAML nodes as-is so no group field is lost on the way to the file.
+		content, err := yaml.Marshal(struct {
+			Groups []yaml.Node `yaml:"groups"`
+		}{mixed})
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode rule groups of namespace '%s': %v", ns, err)
+		}
+		resName := uniqueResourceName(names, "mimir_rules", ns)
+		contentPath := "rules/" + resName + ".yaml"
+		files = append(files, generatedFile{path: contentPath, content: content})
+
+		data := map[string]interface{}{
+			orgIDKey:     orgID,
+			namespaceKey: ns,
+			// content (not content_file) matches what the mimir_rules importer reads back,
+			// so the first plan after import is empty.
+			"content": hclExpr(`file("${path.module}/` + contentPath + `")`),
+		}
+		// In typed mode, the groups of the typed resources are left out of only_groups and
+		// of the import ID, so mimir_rules does not delete them on the first apply.
+		var onlyGroups []string
+		if rulesMode == generateRulesModeTyped {
+			onlyGroups = mixedNames
+			data["only_groups"] = mixedNames
+		}
+		writeImportBlock(&imports, "mimir_rules", resName, buildRulesImportID(orgID, ns, onlyGroups))
+		writeResourceBlock(&rules, "mimir_rules", resName, resourceMimirRules().Schema, data)
+	}
+
+	amData, err := generateReadAlertmanagerConfig(ctx, client, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if amData != nil {
+		resName := uniqueResourceName(names, "mimir_alertmanager_config", orgID)
+		writeImportBlock(&imports, "mimir_alertmanager_config", resName, orgID)
+		writeResourceBlock(&alertmanager, "mimir_alertmanager_config", resName, resourceMimirAlertmanagerConfigSchemaV1(), amData)
+	}
+
+	for _, f := range []struct {
+		path string
+		body *strings.Builder
+	}{
+		{"imports.tf", &imports},
+		{"rules.tf", &rules},
+		{"alertmanager.tf", &alertmanager},
+	} {
+		if f.body.Len() == 0 {
+			continue
+		}
+		files = append(files, generatedFile{path: f.path, content: hclwrite.Format([]byte(f.body.String()))})
+	}
+
+	return files, nil
+}
+
+// generateReadRuleNamespaces lists every rule group of the tenant, keyed by namespace. Groups
+// are kept as YAML nodes so each one can be decoded into the typed struct it belongs to.
+func generateReadRuleNamespaces(ctx context.Context, client *apiClient, orgID string) (map[string][]yaml.Node, error) {
+	headers := map[string]string{"X-Scope-OrgID": orgID}
+	resp, err := client.sendRequest(ctx, "ruler", "GET", rulesListPath(), "", headers)
+	err = handleHTTPError(err, "Cannot list rule groups -")
+	if err != nil {
+		if strings.Contains(err.Error(), "response code '404'") {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var namespaces map[string][]yaml.Node
+	if err := yaml.Unmarshal([]byte(resp), &namespaces); err != nil {
+		return nil, fmt.Errorf("unable to decode rule groups listing: %v", err)
+	}
+	return namespaces, nil
+}
+
+// generateReadAlertmanagerConfig returns the tenant alertmanager config as flattened resource
+// data, or nil when the tenant has no (or an empty) config.
+func generateReadAlertmanagerConfig(ctx context.Context, client *apiClient, orgID string) (map[string]interface{}, error) {
+	resp, err := alertmanagerConfigRead(ctx, client, orgID)
+	if err != nil {
+		if strings.Contains(err.Error(), "response code '404'") {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var alertmanagerUserConf alertmanagerUserConfig
+	if err := yaml.Unmarshal([]byte(resp), &alertmanagerUserConf); err != nil {
+		return nil, err
+	}
+	if alertmanagerUserConf.AlertmanagerConfig == "" {
+		return nil, nil
+	}
+
+	var alertmanagerConf alertmanagerConfig
+	if err := yaml.Unmarshal([]byte(alertmanagerUserConf.AlertmanagerConfig), &alertmanagerConf); err != nil {
+		return nil, err
+	}
+
+	data := map[string]interface{}{
+		orgIDKey:          orgID,
+		"time_interval":   flattenMuteTimeIntervalConfig(alertmanagerConf.MuteTimeIntervals),
+		"inhibit_rule":    flattenInhibitRuleConfig(alertmanagerConf.InhibitRules),
+		"receiver":        flattenReceiverConfig(alertmanagerConf.Receivers),
+		"templates":       alertmanagerConf.Templates,
+		"templates_files": alertmanagerUserConf.TemplateFiles,
+	}
+	if alertmanagerConf.Global != nil {
+		data["global"] = flattenGlobalConfig(alertmanagerConf.Global)
+	}
+	if alertmanagerConf.Route != nil {
+		data["route"] = flattenRouteConfig(alertmanagerConf.Route)
+	}
+	return data, nil
+}
+
+// ruleGroupKind reports whether a group only holds alerting ("alerting") or only recording
+// ("recording") rules. ok is false for a group mixing both kinds.
+func ruleGroupKind(group RuleGroup) (kind string, ok bool) {
+	var alerting, recording int
+	for _, rule := range group.Rules {
+		if rule.Alert != "" {
+			alerting++
+		} else if rule.Record != "" {
+			recording++
+		}
+	}
+	switch {
+	case alerting > 0 && recording == 0:
+		return "alerting", true
+	case recording > 0 && alerting == 0:
+		return "recording", true
+	}
+	return "", false
+}
+
+// uniqueResourceName turns a free-form name into a valid Terraform resource name that is not
+// yet used for resourceType, recording it in names.
+func uniqueResourceName(names map[string]bool, resourceType, name string) string {
+	base := strings.ToLower(tfIdentifierInvalidCharRegexp.ReplaceAllString(name, "_"))
+	if base == "" || !(base[0] == '_' || (base[0] >= 'a' && base[0] <= 'z')) {
+		base = "_" + base
+	}
+	candidate := base
+	for i := 2; names[resourceType+"."+candidate]; i++ {
+		candidate = fmt.Sprintf("%s_%d", base, i)
+	}
+	names[resourceType+"."+candidate] = true
+	return candidate
+}
+
+func writeImportBlock(b *strings.Builder, resourceType, name, id string) {
+	fmt.Fprintf(b, "import {\nto = %s.%s\nid = %s\n}\n\n", resourceType, name, hclQuote(id))
+}
+
+func writeResourceBlock(b *strings.Builder, resourceType, name string, sch map[string]*schema.Schema, data map[string]interface{}) {
+	fmt.Fprintf(b, "resource %q %q {\n", resourceType, name)
+	writeHCLBody(b, sch, data)
+	b.WriteString("}\n\n")
+}
+
+// writeHCLBody renders flattened resource data as HCL, driven by the resource schema:
+// attributes first, then nested blocks, each in alphabetical order. Computed-only fields and
+// values equal to their default (or unset) are omitted.
+func writeHCLBody(b *strings.Builder, sch map[string]*schema.Schema, data map[string]interface{}) {
+	var attrs, blocks []string
+	for k, s := range sch {
+		if !s.Optional && !s.Required {
+			continue
+		}
+		v, ok := data[k]
+		if !ok || skipHCLValue(s, v) {
+			continue
+		}
+		if _, isBlock := s.Elem.(*schema.Resource); isBlock {
+			blocks = append(blocks, k)
+		} else {
+			attrs = append(attrs, k)
+		}
+	}
+	sort.Strings(attrs)
+	sort.Strings(blocks)
+
+	for _, k := range attrs {
+		fmt.Fprintf(b, "%s = %s\n", k, hclValue(reflect.ValueOf(data[k])))
+	}
+	for _, k := range blocks {
+		elem := sch[k].Elem.(*schema.Resource)
+		items := reflect.ValueOf(data[k])
+		for i := 0; i < items.Len(); i++ {
+			item := hclItemMap(items.Index(i))
+			if item == nil {
+				continue
+			}
+			fmt.Fprintf(b, "%s {\n", k)
+			writeHCLBody(b, elem.Schema, item)
+			b.WriteString("}\n")
+		}
+	}
+}
+
+// skipHCLValue reports whether v must be omitted: unset values, and values equal to the
+// schema default, which Terraform fills in on its own.
+func skipHCLValue(s *schema.Schema, v interface{}) bool {
+	rv := indirectValue(reflect.ValueOf(v))
+	if !rv.IsValid() {
+		return true
+	}
+	if s.Default != nil {
+		return fmt.Sprint(rv.Interface()) == fmt.Sprint(s.Default)
+	}
+	if _, isPtr := v.(*bool); isPtr {
+		// An explicitly set boolean pointer is meaningful even when false.
+		return false
+	}
+	return rv.IsZero() || ((rv.Kind() == reflect.Slice || rv.Kind() == reflect.Map) && rv.Len() == 0)
+}
+
+func indirectValue(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func hclItemMap(v reflect.Value) map[string]interface{} {
+	v = indirectValue(v)
+	if !v.IsValid() || v.Kind() != reflect.Map {
+		return nil
+	}
+	m := make(map[string]interface{}, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		m[iter.Key().String()] = iter.Value().Interface()
+	}
+	return m
+}
+
+func hclValue(v reflect.Value) string {
+	v = indirectValue(v)
+	if !v.IsValid() {
+		return "null"
+	}
+	if e, ok := v.Interface().(hclExpr); ok {
+		return string(e)
+	}
+	if s, ok := v.Interface().(fmt.Stringer); ok && v.Kind() == reflect.Struct {
+		return hclQuote(s.String())
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return hclQuote(v.String())
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64)
+	case reflect.Slice, reflect.Array:
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = hclValue(v.Index(i))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case reflect.Map:
+		keys := make([]string, 0, v.Len())
+		values := make(map[string]string, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			k := fmt.Sprint(iter.Key().Interface())
+			keys = append(keys, k)
+			values[k] = hclValue(iter.Value())
+		}
+		sort.Strings(keys)
+		var b strings.Builder
+		b.WriteString("{\n")
+		for _, k := range keys {
+			key := k
+			if !tfIdentifierRegexp.MatchString(k) {
+				key = hclQuote(k)
+			}
+			fmt.Fprintf(&b, "%s = %s\n", key, values[k])
+		}
+		b.WriteString("}")
+		return b.String()
+	}
+	return hclQuote(fmt.Sprint(v.Interface()))
+}
+
+// hclQuote renders s as an HCL quoted template. Only the escapes HCL understands are used,
+// and the interpolation and directive introducers are doubled so Prometheus/Alertmanager
+// templates are kept literally.
+func hclQuote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case (r == '$' || r == '%') && strings.HasPrefix(s[i+1:], "{"):
+			b.WriteRune(r)
+			b.WriteRune(r)
+		case !unicode.IsPrint(r):
+			fmt.Fprintf(&b, `\u%04X`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
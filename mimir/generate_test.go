@@ -0,0 +1,249 @@
+package mimir
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"gopkg.in/yaml.v3"
+)
+
+const generateTestRules = `team a:
+- name: alerts
+  interval: 1m
+  rules:
+  - alert: InstanceDown
+    expr: up == 0
+    for: 5m
+    labels:
+      severity: critical
+    annotations:
+      summary: '{{ $labels.instance }} is down (${not an interpolation})'
+- name: records
+  rules:
+  - record: job:up:sum
+    expr: sum by (job) (up)
+- name: mixed
+  rules:
+  - alert: Down
+    expr: up == 0
+  - record: job:up:count
+    expr: count by (job) (up)
+`
+
+const generateTestAlertmanager = `template_files:
+  default_template: '{{ define "__alertmanager" }}AlertManager{{ end }}'
+alertmanager_config: |
+  route:
+    receiver: pagerduty
+    group_by: [alertname]
+  receivers:
+  - name: pagerduty
+    pagerduty_configs:
+    - routing_key: secret
+`
+
+// generateTestHandler is a stand-in for the ruler and alertmanager APIs of one tenant.
+func generateTestHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(rulesListPath(), func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Scope-OrgID") != "tenant-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(generateTestRules))
+	})
+	mux.HandleFunc(apiAlertsPath, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(generateTestAlertmanager))
+	})
+	return mux
+}
+
+func generateTestFiles(t *testing.T, mode string) map[string]string {
+	t.Helper()
+	srv := newTestServer(t, generateTestHandler())
+	client := newTestClient(t, &apiClientOpt{uri: srv.URL, headers: map[string]string{}, timeout: 2})
+	files, err := generateConfig(context.Background(), client, "tenant-1", mode)
+	if err != nil {
+		t.Fatalf("generateConfig: %v", err)
+	}
+	out := make(map[string]string)
+	for _, f := range files {
+		out[f.path] = string(f.content)
+		if strings.HasSuffix(f.path, ".tf") {
+			if _, diags := hclsyntax.ParseConfig(f.content, f.path, hcl.InitialPos); diags.HasErrors() {
+				t.Fatalf("%s is not valid HCL: %v\n%s", f.path, diags, f.content)
+			}
+		}
+	}
+	return out
+}
+
+func TestGenerateConfig_Typed(t *testing.T) {
+	files := generateTestFiles(t, generateRulesModeTyped)
+
+	imports := files["imports.tf"]
+	for _, want := range []string{
+		`to = mimir_rule_group_alerting.team_a_alerts`,
+		`id = "tenant-1/team%20a/alerts"`,
+		`to = mimir_rule_group_recording.team_a_records`,
+		`to = mimir_rules.team_a`,
+		`id = "tenant-1/team%20a?groups=mixed"`,
+		`to = mimir_alertmanager_config.tenant-1`,
+	} {
+		if !strings.Contains(imports, want) {
+			t.Errorf("imports.tf missing %q:\n%s", want, imports)
+		}
+	}
+
+	rules := files["rules.tf"]
+	for _, want := range []string{
+		`resource "mimir_rule_group_alerting" "team_a_alerts" {`,
+		`alert = "InstanceDown"`,
+		`summary = "{{ $labels.instance }} is down ($${not an interpolation})"`,
+		`record = "job:up:sum"`,
+		`only_groups = ["mixed"]`,
+		`content     = file("${path.module}/rules/team_a.yaml")`,
+	} {
+		if !strings.Contains(rules, want) {
+			t.Errorf("rules.tf missing %q:\n%s", want, rules)
+		}
+	}
+	if strings.Contains(rules, "evaluation_delay") {
+		t.Errorf("rules.tf renders an unset attribute:\n%s", rules)
+	}
+
+	var content struct {
+		Groups []RuleGroup `yaml:"groups"`
+	}
+	if err := yaml.Unmarshal([]byte(files["rules/team_a.yaml"]), &content); err != nil {
+		t.Fatal(err)
+	}
+	if len(content.Groups) != 1 || content.Groups[0].Name != "mixed" {
+		t.Errorf("rules/team_a.yaml should only hold the mixed group, got %+v", content.Groups)
+	}
+
+	am := files["alertmanager.tf"]
+	for _, want := range []string{
+		`resource "mimir_alertmanager_config" "tenant-1" {`,
+		`routing_key = "secret"`,
+		`receiver = "pagerduty"`,
+		`group_by = ["alertname"]`,
+	} {
+		if !strings.Contains(am, want) {
+			t.Errorf("alertmanager.tf missing %q:\n%s", want, am)
+		}
+	}
+}
+
+func TestGenerateConfig_Rules(t *testing.T) {
+	files := generateTestFiles(t, generateRulesModeRules)
+
+	if strings.Contains(files["rules.tf"], "mimir_rule_group_") {
+		t.Errorf("rules mode must not emit typed rule group resources:\n%s", files["rules.tf"])
+	}
+	if strings.Contains(files["rules.tf"], "only_groups") {
+		t.Errorf("rules mode must manage every group of the namespace:\n%s", files["rules.tf"])
+	}
+
+	var ruleGroups RuleGroups
+	if err := yaml.Unmarshal([]byte(files["rules/team_a.yaml"]), &ruleGroups); err != nil {
+		t.Fatal(err)
+	}
+	if err := validateRuleGroupsContent(ruleGroups); err != nil {
+		t.Fatalf("generated content does not validate: %v", err)
+	}
+	if len(ruleGroups.Groups) != 3 {
+		t.Errorf("expected 3 groups in rules/team_a.yaml, got %d", len(ruleGroups.Groups))
+	}
+}
+
+func TestGenerateConfig_InvalidMode(t *testing.T) {
+	if _, err := generateConfig(context.Background(), nil, "tenant-1", "bogus"); err == nil {
+		t.Error("expected an error for an unknown rules resource mode")
+	}
+}
+
+func TestUniqueResourceName(t *testing.T) {
+	names := make(map[string]bool)
+	cases := []struct{ in, want string }{
+		{"Team A/alerts", "team_a_alerts"},
+		{"team a alerts", "team_a_alerts_2"},
+		{"1st", "_1st"},
+		{"", "_"},
+	}
+	for _, c := range cases {
+		if got := uniqueResourceName(names, "mimir_rules", c.in); got != c.want {
+			t.Errorf("uniqueResourceName(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
+
+// TestGenerateConfig_TypedImport imports the generated mimir_rules block and checks the
+// first plan is empty, so no group of the typed resources is deleted.
+func TestGenerateConfig_TypedImport(t *testing.T) {
+	files := generateTestFiles(t, generateRulesModeTyped)
+	match := regexp.MustCompile(`to = mimir_rules\.team_a\s+id = "([^"]+)"`).FindStringSubmatch(files["imports.tf"])
+	if match == nil {
+		t.Fatalf("no mimir_rules import block:\n%s", files["imports.tf"])
+	}
+
+	var byNamespace map[string][]RuleGroup
+	if err := yaml.Unmarshal([]byte(generateTestRules), &byNamespace); err != nil {
+		t.Fatal(err)
+	}
+	var deletes []string
+	srv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "DELETE" {
+			deletes = append(deletes, r.URL.Path)
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		if r.URL.EscapedPath() == rulesNamespacePath("team a") {
+			_, _ = w.Write([]byte(generateTestRules))
+			return
+		}
+		for _, group := range byNamespace["team a"] {
+			if r.URL.EscapedPath() == rulesGroupPath("team a", group.Name) {
+				data, _ := yaml.Marshal(group)
+				_, _ = w.Write(data)
+				return
+			}
+		}
+		http.Error(w, "group does not exist", http.StatusNotFound)
+	}))
+	client := newTestClient(t, &apiClientOpt{uri: srv.URL, headers: map[string]string{}, timeout: 2})
+
+	ctx := context.Background()
+	r := resourceMimirRules()
+	imported, err := r.Importer.StateContext(ctx, r.Data(&terraform.InstanceState{ID: match[1]}), client)
+	if err != nil {
+		t.Fatalf("import %s: %v", match[1], err)
+	}
+	state, diags := r.RefreshWithoutUpgrade(ctx, imported[0].State(), client)
+	if diags.HasError() {
+		t.Fatalf("refresh: %v", diags)
+	}
+
+	cfg := terraform.NewResourceConfigRaw(map[string]interface{}{
+		orgIDKey:      "tenant-1",
+		namespaceKey:  "team a",
+		"content":     files["rules/team_a.yaml"],
+		"only_groups": []interface{}{"mixed"},
+	})
+	diff, err := r.Diff(ctx, state, cfg, client)
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	if !diff.Empty() {
+		t.Errorf("expected an empty plan after import, got %v", diff.Attributes)
+	}
+	if len(deletes) != 0 {
+		t.Errorf("expected no group to be deleted, got %v", deletes)
+	}
+}
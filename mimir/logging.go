@@ -0,0 +1,138 @@
+package mimir
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	logFieldRequestHeaderPrefix  = "request_header."
+	logFieldResponseHeaderPrefix = "response_header."
+)
+
+// secretHeaders are masked in the logs, like the headers configured with headers or
+// request_headers, which often carry credentials.
+var secretHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Amz-Security-Token",
+}
+
+// secretBodyKeys matches the keys of the Alertmanager config (and other payloads) holding
+// secrets, e.g. auth_password, bot_token, api_key, routing_key or a Slack api_url.
+const secretBodyKeys = `[a-z0-9_]*(?:password|secret|token|api_key|credentials|routing_key|service_key|user_key|api_url|webhook_url|private_key)[a-z0-9_]*`
+
+var (
+	secretYAMLValueRegexp = regexp.MustCompile(`(?mi)^(\s*(?:-\s+)?` + secretBodyKeys + `\s*:[ \t]*)[^\s|>#].*$`)
+	secretYAMLBlockRegexp = regexp.MustCompile(`(?i)^(\s*(?:-\s+)?)(` + secretBodyKeys + `\s*:[ \t]*)[|>][-+0-9]*[ \t]*(?:#.*)?$`)
+	secretJSONValueRegexp = regexp.MustCompile(`(?i)("` + secretBodyKeys + `"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+)
+
+// redactBody masks the values of the secret keys of a YAML or JSON body.
+func redactBody(body string) string {
+	body = secretJSONValueRegexp.ReplaceAllString(body, `${1}"***"`)
+	body = secretYAMLValueRegexp.ReplaceAllString(body, `${1}"***"`)
+	return redactYAMLBlockScalars(body)
+}
+
+// redactYAMLBlockScalars masks the literal and folded block scalars of the secret keys,
+// e.g. a multi-line password: |, dropping their lines more indented than the key.
+func redactYAMLBlockScalars(body string) string {
+	lines := strings.Split(body, "\n")
+	out := lines[:0]
+	for i := 0; i < len(lines); i++ {
+		m := secretYAMLBlockRegexp.FindStringSubmatch(lines[i])
+		if m == nil {
+			out = append(out, lines[i])
+			continue
+		}
+		out = append(out, m[1]+m[2]+`"***"`)
+		// The block ends before the first line indented as much as the key, blank lines
+		// belonging to it only when followed by more of it.
+		indent := len(m[1])
+		for j := i + 1; j < len(lines); j++ {
+			if strings.TrimSpace(lines[j]) == "" {
+				continue
+			}
+			if len(lines[j])-len(strings.TrimLeft(lines[j], " \t")) <= indent {
+				break
+			}
+			i = j
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// requestLogContext returns ctx with the fields describing a request, and the masking of
+// its secret headers: the well known ones and those named in configured.
+func requestLogContext(ctx context.Context, component, method, path, orgID string, configured ...map[string]string) context.Context {
+	if component == "" {
+		component = "default"
+	}
+	ctx = tflog.SetField(ctx, "component", component)
+	ctx = tflog.SetField(ctx, "method", method)
+	ctx = tflog.SetField(ctx, "path", path)
+	ctx = tflog.SetField(ctx, "org_id", orgID)
+
+	var masked []string
+	for _, name := range secretHeaders {
+		masked = append(masked, logFieldRequestHeaderPrefix+strings.ToLower(name), logFieldResponseHeaderPrefix+strings.ToLower(name))
+	}
+	for _, headers := range configured {
+		for name := range headers {
+			if c := http.CanonicalHeaderKey(name); c == "X-Scope-Orgid" || c == "User-Agent" {
+				continue
+			}
+			masked = append(masked, logFieldRequestHeaderPrefix+strings.ToLower(name))
+		}
+	}
+	return tflog.MaskFieldValuesWithFieldKeys(ctx, masked...)
+}
+
+// headerFields returns the log fields of headers, one per header.
+func headerFields(prefix string, headers http.Header) map[string]interface{} {
+	fields := make(map[string]interface{}, len(headers))
+	for name, values := range headers {
+		fields[prefix+strings.ToLower(name)] = strings.Join(values, ", ")
+	}
+	return fields
+}
+
+func logRequest(ctx context.Context, req *http.Request, body string, debug bool) {
+	if !debug {
+		tflog.Debug(ctx, "Sending request to Mimir")
+		return
+	}
+	fields := headerFields(logFieldRequestHeaderPrefix, req.Header)
+	fields["url"] = req.URL.String()
+	fields["body"] = redactBody(body)
+	tflog.Debug(ctx, "Sending request to Mimir", fields)
+}
+
+func logResponse(ctx context.Context, resp *http.Response, body string, duration time.Duration, debug bool) {
+	fields := map[string]interface{}{
+		"status":      resp.StatusCode,
+		"duration_ms": duration.Milliseconds(),
+	}
+	if debug {
+		for k, v := range headerFields(logFieldResponseHeaderPrefix, resp.Header) {
+			fields[k] = v
+		}
+		fields["body"] = redactBody(body)
+	}
+	tflog.Debug(ctx, "Received response from Mimir", fields)
+}
+
+func logRequestError(ctx context.Context, err error, duration time.Duration) {
+	tflog.Debug(ctx, "Request to Mimir failed", map[string]interface{}{
+		"error":       err.Error(),
+		"duration_ms": duration.Milliseconds(),
+	})
+}
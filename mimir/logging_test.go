@@ -0,0 +1,106 @@
+package mimir
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func TestRedactBody(t *testing.T) {
+	body := `global:
+  smtp_auth_password: hunter2
+  slack_api_url: 'https://hooks.slack.com/services/T0/B0/XXX'
+receivers:
+  - name: pager
+    pagerduty_configs:
+      - routing_key: abcdef
+        send_resolved: true
+  - name: telegram
+    telegram_configs:
+      - bot_token: "123:abc"
+`
+	got := redactBody(body)
+	for _, secret := range []string{"hunter2", "hooks.slack.com", "abcdef", "123:abc"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("secret %q not redacted:\n%s", secret, got)
+		}
+	}
+	for _, kept := range []string{"name: pager", "send_resolved: true", `smtp_auth_password: "***"`} {
+		if !strings.Contains(got, kept) {
+			t.Errorf("expected %q in:\n%s", kept, got)
+		}
+	}
+
+	got = redactBody("global:\n  smtp_auth_password: |\n    hunter2\n\n    line2\n\n  smtp_from: am@example.com\nreceivers:\n  - name: x\n    webhook_configs:\n      - http_config:\n          bearer_token: >-\n            folded\n            token\n        url: http://x\n")
+	want := "global:\n  smtp_auth_password: \"***\"\n\n  smtp_from: am@example.com\nreceivers:\n  - name: x\n    webhook_configs:\n      - http_config:\n          bearer_token: \"***\"\n        url: http://x\n"
+	if got != want {
+		t.Errorf("block scalars: got\n%s\nwant\n%s", got, want)
+	}
+
+	got = redactBody(`{"webhook_url": "https://example.com/secret", "name": "team", "password":"p\"w"}`)
+	if want := `{"webhook_url": "***", "name": "team", "password":"***"}`; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestSendRequestLogging(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=s3cr3t")
+		_, _ = w.Write([]byte("alertmanager_config: |\n  global:\n    smtp_auth_password: hunter2\n"))
+	}))
+	defer srv.Close()
+
+	client, err := NewAPIClient(&apiClientOpt{
+		uri:     srv.URL,
+		token:   "supersecrettoken",
+		headers: map[string]string{"X-Scope-OrgID": "mytenant", "Custom-Auth": "customsecret"},
+		timeout: 2,
+		debug:   true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+	if _, err := client.sendRequest(ctx, "alertmanager", "POST", apiAlertsPath, "receivers:\n- name: x\n  webhook_configs:\n  - url: http://x\n    http_config:\n      bearer_token: tokenvalue\n", map[string]string{}); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected a request and a response entry, got %d: %s", len(entries), output.String())
+	}
+	for _, secret := range []string{"supersecrettoken", "customsecret", "hunter2", "s3cr3t", "tokenvalue"} {
+		if strings.Contains(output.String(), secret) {
+			t.Errorf("secret %q logged", secret)
+		}
+	}
+
+	response := entries[1]
+	for k, want := range map[string]interface{}{
+		"component": "alertmanager",
+		"method":    "POST",
+		"path":      apiAlertsPath,
+		"org_id":    "mytenant",
+		"status":    float64(200),
+	} {
+		if response[k] != want {
+			t.Errorf("field %s: got %v, want %v", k, response[k], want)
+		}
+	}
+	if _, ok := response["duration_ms"]; !ok {
+		t.Error("expected a duration_ms field")
+	}
+	if got := entries[0]["request_header.authorization"]; got != "***" {
+		t.Errorf("expected the authorization header to be masked, got %v", got)
+	}
+}
@@ -2,9 +2,15 @@ package mimir
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/yaml.v3"
 )
 
 func TestValidRuleName(t *testing.T) {
@@ -175,37 +181,121 @@ func TestParseRuleGroupID(t *testing.T) {
 	}
 }
 
-// TestResourceMimirRulesImport pins the importer's re-validation guards directly (no server
-// needed: the importer never touches its meta parameter). Without this, deleting the
-// validOrgID/validNamespace blocks passes the entire suite (verified by mutation).
+// TestResourceMimirRulesImport pins the importer's re-validation guards and the content it
+// reads back from a stand-in ruler. Without this, deleting the validOrgID/validNamespace
+// blocks passes the entire suite (verified by mutation).
 func TestResourceMimirRulesImport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ns := strings.TrimPrefix(r.URL.Path, rulesNamespacePath(""))
+		_, _ = fmt.Fprintf(w, "%s:\n- name: g1\n  rules:\n  - alert: A\n    expr: up == 0\n- name: g,2\n  rules:\n  - record: job:up:sum\n    expr: sum(up)\n", ns)
+	}))
+	defer srv.Close()
+	client, err := NewAPIClient(&apiClientOpt{uri: srv.URL, headers: map[string]string{}, timeout: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	res := resourceMimirRules()
 	cases := []struct {
 		id      string
 		wantErr bool
 		org, ns string
+		groups  []string
 	}{
-		{"tenant/ns", false, "tenant", "ns"},
-		{"org/team a", false, "org", "team a"}, // spaces in a namespace are accepted
-		{"org/..", true, "", ""},               // dot-segment namespace
-		{"bad\rorg/ns", true, "", ""},          // control char in org_id
-		{"a/b/c", true, "", ""},                // wrong segment count
-		{"noslash", true, "", ""},              // wrong segment count
+		{"tenant/ns", false, "tenant", "ns", nil},
+		{"org/team a", false, "org", "team a", nil},   // spaces in a namespace are accepted
+		{"org/team%20a", false, "org", "team a", nil}, // and may be escaped
+		{"tenant/ns?groups=g1", false, "tenant", "ns", []string{"g1"}},
+		{"tenant/ns?groups=g%2C2,g1", false, "tenant", "ns", []string{"g,2", "g1"}},
+		{"tenant/ns?groups=missing", true, "", "", nil}, // unknown group
+		{"tenant/ns?limit=1", true, "", "", nil},        // unknown parameter
+		{"tenant/ns?groups=..", true, "", "", nil},      // dot-segment group name
+		{"org/..", true, "", "", nil},                   // dot-segment namespace
+		{"org/a%2Fb", true, "", "", nil},                // '/' smuggled into the namespace
+		{"bad\rorg/ns", true, "", "", nil},              // control char in org_id
+		{"a/b/c", true, "", "", nil},                    // wrong segment count
+		{"noslash", true, "", "", nil},                  // wrong segment count
 	}
 	for _, c := range cases {
 		d := schema.TestResourceDataRaw(t, res.Schema, map[string]interface{}{})
 		d.SetId(c.id)
-		got, err := resourceMimirRulesImport(context.Background(), d, nil)
+		got, err := resourceMimirRulesImport(context.Background(), d, client)
 		if (err != nil) != c.wantErr {
 			t.Errorf("resourceMimirRulesImport(%q) err=%v, wantErr=%v", c.id, err, c.wantErr)
 			continue
 		}
-		if err == nil {
-			if len(got) != 1 || got[0].Get(orgIDKey).(string) != c.org || got[0].Get(namespaceKey).(string) != c.ns {
-				t.Errorf("resourceMimirRulesImport(%q) set org_id/namespace = %q/%q, want %q/%q",
-					c.id, got[0].Get(orgIDKey), got[0].Get(namespaceKey), c.org, c.ns)
-			}
+		if err != nil {
+			continue
+		}
+		if len(got) != 1 || got[0].Get(orgIDKey).(string) != c.org || got[0].Get(namespaceKey).(string) != c.ns {
+			t.Errorf("resourceMimirRulesImport(%q) set org_id/namespace = %q/%q, want %q/%q",
+				c.id, got[0].Get(orgIDKey), got[0].Get(namespaceKey), c.org, c.ns)
+			continue
+		}
+		if got[0].Get("content_format") != ruleContentFormatRules {
+			t.Errorf("resourceMimirRulesImport(%q) content_format = %q, want %q", c.id, got[0].Get("content_format"), ruleContentFormatRules)
+		}
+		if got[0].Id() != c.org+"/"+c.ns {
+			t.Errorf("resourceMimirRulesImport(%q) id = %q, want %q", c.id, got[0].Id(), c.org+"/"+c.ns)
 		}
+		only := expandStringArray(got[0].Get("only_groups").(*schema.Set).List())
+		sort.Strings(only)
+		sort.Strings(c.groups)
+		if strings.Join(only, "|") != strings.Join(c.groups, "|") {
+			t.Errorf("resourceMimirRulesImport(%q) only_groups = %v, want %v", c.id, only, c.groups)
+		}
+		var ruleGroups RuleGroups
+		if err := yaml.Unmarshal([]byte(got[0].Get("content").(string)), &ruleGroups); err != nil || len(ruleGroups.Groups) != 2 {
+			t.Errorf("resourceMimirRulesImport(%q) content = %q (err %v), want both groups", c.id, got[0].Get("content"), err)
+		}
+	}
+}
+
+func TestBuildRulesImportID(t *testing.T) {
+	id := buildRulesImportID("tenant", "team a", []string{"g,2", "a b"})
+	if id != "tenant/team%20a?groups=g%2C2,a+b" {
+		t.Errorf("unexpected import ID %q", id)
+	}
+	orgID, namespace, groups, err := parseRulesImportID(id)
+	if err != nil || orgID != "tenant" || namespace != "team a" || strings.Join(groups, "|") != "g,2|a b" {
+		t.Errorf("parseRulesImportID(%q) = %q, %q, %v, %v", id, orgID, namespace, groups, err)
+	}
+	if id := buildRulesImportID("tenant", "ns", nil); id != "tenant/ns" {
+		t.Errorf("unexpected import ID %q without groups", id)
+	}
+}
+
+func TestSuppressEquivalentRuleGroupsContent(t *testing.T) {
+	imported := "groups:\n    - name: g1\n      rules:\n        - expr: up == 0\n          alert: A\n"
+	handWritten := "groups:\n- name: g1\n  rules:\n  - alert: A\n    expr: 'up == 0'\n"
+	if !suppressEquivalentRuleGroupsContent("content", imported, handWritten, nil) {
+		t.Error("expected equivalent rule group documents to suppress the diff")
+	}
+	changed := "groups:\n- name: g1\n  rules:\n  - alert: A\n    expr: up == 1\n"
+	if suppressEquivalentRuleGroupsContent("content", imported, changed, nil) {
+		t.Error("expected a changed expression to show a diff")
+	}
+	if suppressEquivalentRuleGroupsContent("content", "", handWritten, nil) {
+		t.Error("expected a diff when content is first set")
+	}
+
+	// Groups not managed are left out of the comparison
+	namespace := imported + "    - name: g2\n      rules:\n        - record: job:up:sum\n          expr: sum(up)\n"
+	d := schema.TestResourceDataRaw(t, resourceMimirRules().Schema, map[string]interface{}{
+		"content":     handWritten,
+		"only_groups": []interface{}{"g1"},
+	})
+	if !suppressEquivalentRuleGroupsContent("content", namespace, handWritten, d) {
+		t.Error("expected a group left out by only_groups to be ignored")
+	}
+	if suppressEquivalentRuleGroupsContent("content", namespace, changed, d) {
+		t.Error("expected a changed managed group to show a diff")
+	}
+	d = schema.TestResourceDataRaw(t, resourceMimirRules().Schema, map[string]interface{}{
+		"content": handWritten,
+	})
+	if suppressEquivalentRuleGroupsContent("content", namespace, handWritten, d) {
+		t.Error("expected a removed group to show a diff when every group is managed")
 	}
 }
 
@@ -32,14 +32,14 @@ func TestRulesGroupPath(t *testing.T) {
 }
 
 // TestNoRawRulerPath is a structural guard: the ruler-path literal may appear ONLY inside
-// the two sanctioned helpers. It walks every string literal in the package — including
+// the three sanctioned helpers. It walks every string literal in the package — including
 // package-level var/const initializers, closures, and ALL _test.go files (the round-3 defect
 // class was a raw path in a test helper; AC4 requires product AND test coverage) — and fails
-// on any occurrence outside rulesGroupPath/rulesNamespacePath. Only this file is exempt (it
+// on any occurrence outside rulesGroupPath/rulesNamespacePath/rulesListPath. Only this file is exempt (it
 // holds the marker constant and the helpers' expected-output literals).
 func TestNoRawRulerPath(t *testing.T) {
 	const marker = "/config" + "/v1" + "/rules" // split so this literal does not flag itself elsewhere
-	allowed := map[string]bool{"rulesGroupPath": true, "rulesNamespacePath": true}
+	allowed := map[string]bool{"rulesGroupPath": true, "rulesNamespacePath": true, "rulesListPath": true}
 
 	files, err := filepath.Glob("*.go")
 	if err != nil {
@@ -72,7 +72,7 @@ func TestNoRawRulerPath(t *testing.T) {
 		}
 		ast.Inspect(f, func(n ast.Node) bool {
 			if lit, ok := n.(*ast.BasicLit); ok && lit.Kind == token.STRING && strings.Contains(lit.Value, marker) && !inAllowed(lit.Pos()) {
-				t.Errorf("%s: raw ruler path literal %s at %s is outside rulesGroupPath/rulesNamespacePath; use the helpers", fn, lit.Value, fset.Position(lit.Pos()))
+				t.Errorf("%s: raw ruler path literal %s at %s is outside rulesGroupPath/rulesNamespacePath/rulesListPath; use the helpers", fn, lit.Value, fset.Position(lit.Pos()))
 			}
 			return true
 		})
@@ -0,0 +1,424 @@
+package mimir
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+const (
+	promQLCheckRateOnGauge              = "rate_on_gauge"
+	promQLCheckRangeTooShort            = "range_too_short"
+	promQLCheckAggregationDropsLabels   = "aggregation_drops_labels"
+	promQLCheckAbsent                   = "absent"
+	promQLCheckRecordingRuleAggregation = "recording_rule_aggregation"
+
+	// defaultRuleGroupInterval is the Mimir ruler evaluation_interval default, used for
+	// groups without an interval.
+	defaultRuleGroupInterval = time.Minute
+)
+
+// promQLChecks lists the semantic checks run on rule expressions, in the order they
+// are documented.
+var promQLChecks = []struct {
+	name        string
+	description string
+}{
+	{promQLCheckRateOnGauge, "Warn when `rate()`, `irate()` or `increase()` is applied to a metric that does not look like a counter (name not ending in `_total`, `_count`, `_sum` or `_bucket`)."},
+	{promQLCheckRangeTooShort, "Warn when a range selector or subquery is shorter than twice the group evaluation interval."},
+	{promQLCheckAggregationDropsLabels, "Warn when an alert annotation references a label (`$labels.<name>`) removed by the expression, e.g. by an aggregation."},
+	{promQLCheckAbsent, "Warn on `absent()` misuse: wrapping something else than a plain selector, comparing its result, or using it in a recording rule."},
+	{promQLCheckRecordingRuleAggregation, "Warn when the operations part of a `level:metric:operations` recording rule name mentions another aggregation than the outer aggregation of its expression."},
+}
+
+// enabledPromQLChecks holds the provider-level promql_checks settings, keyed by check name.
+var enabledPromQLChecks = defaultPromQLChecks()
+
+func defaultPromQLChecks() map[string]bool {
+	checks := make(map[string]bool, len(promQLChecks))
+	for _, c := range promQLChecks {
+		checks[c.name] = true
+	}
+	return checks
+}
+
+func promQLChecksSchema() *schema.Resource {
+	s := make(map[string]*schema.Schema, len(promQLChecks))
+	for _, c := range promQLChecks {
+		s[c.name] = &schema.Schema{
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     true,
+			Description: c.description,
+		}
+	}
+	return &schema.Resource{Schema: s}
+}
+
+// expandPromQLChecks reads the promql_checks block; every check is enabled when the block is absent.
+func expandPromQLChecks(v []interface{}) map[string]bool {
+	checks := defaultPromQLChecks()
+	if len(v) == 0 || v[0] == nil {
+		return checks
+	}
+	data := v[0].(map[string]interface{})
+	for name := range checks {
+		if enabled, ok := data[name].(bool); ok {
+			checks[name] = enabled
+		}
+	}
+	return checks
+}
+
+var (
+	counterSuffixes = []string{"_total", "_count", "_sum", "_bucket"}
+
+	aggregationNames = map[string]bool{
+		"sum": true, "avg": true, "min": true, "max": true, "count": true, "group": true,
+		"stddev": true, "stdvar": true, "topk": true, "bottomk": true, "quantile": true,
+	}
+
+	// templateLabelRegexps match the label references of alert templates.
+	templateLabelRegexps = []*regexp.Regexp{
+		regexp.MustCompile(`\$labels\.([a-zA-Z_][a-zA-Z0-9_]*)`),
+		regexp.MustCompile(`\.Labels\.([a-zA-Z_][a-zA-Z0-9_]*)`),
+		regexp.MustCompile(`index\s+\$labels\s+"([^"]+)"`),
+	}
+)
+
+// promQLCheckRuleGroups runs the enabled semantic checks on every rule and returns
+// warning-level findings. Expressions that do not parse are left to validateRule.
+func promQLCheckRuleGroups(ruleGroups RuleGroups, checks map[string]bool) []ruleLintFinding {
+	var findings []ruleLintFinding
+	for _, group := range ruleGroups.Groups {
+		interval := defaultRuleGroupInterval
+		if group.Interval != "" {
+			if d, err := model.ParseDuration(group.Interval); err == nil && d > 0 {
+				interval = time.Duration(d)
+			}
+		}
+		for _, rule := range group.Rules {
+			expr, err := parser.NewParser(parser.Options{}).ParseExpr(rule.Expr)
+			if err != nil {
+				continue
+			}
+			ruleName := rule.Alert
+			if ruleName == "" {
+				ruleName = rule.Record
+			}
+			for _, c := range promQLChecks {
+				if !checks[c.name] {
+					continue
+				}
+				var msgs []string
+				switch c.name {
+				case promQLCheckRateOnGauge:
+					msgs = checkRateOnGauge(expr)
+				case promQLCheckRangeTooShort:
+					msgs = checkRangeTooShort(expr, interval)
+				case promQLCheckAggregationDropsLabels:
+					msgs = checkAggregationDropsLabels(expr, group, rule)
+				case promQLCheckAbsent:
+					msgs = checkAbsent(expr, rule)
+				case promQLCheckRecordingRuleAggregation:
+					msgs = checkRecordingRuleAggregation(expr, rule)
+				}
+				for _, msg := range msgs {
+					findings = append(findings, ruleLintFinding{
+						Policy:   "promql/" + c.name,
+						Severity: ruleLintSeverityWarning,
+						Group:    group.Name,
+						Rule:     ruleName,
+						Message:  msg,
+					})
+				}
+			}
+		}
+	}
+	return findings
+}
+
+func checkRateOnGauge(expr parser.Expr) []string {
+	var msgs []string
+	parser.Inspect(expr, func(node parser.Node, _ []parser.Node) error {
+		call, ok := node.(*parser.Call)
+		if !ok || len(call.Args) == 0 {
+			return nil
+		}
+		switch call.Func.Name {
+		case "rate", "irate", "increase":
+		default:
+			return nil
+		}
+		ms, ok := call.Args[0].(*parser.MatrixSelector)
+		if !ok {
+			return nil
+		}
+		vs, ok := ms.VectorSelector.(*parser.VectorSelector)
+		if !ok || vs.Name == "" {
+			return nil
+		}
+		for _, suffix := range counterSuffixes {
+			if strings.HasSuffix(vs.Name, suffix) {
+				return nil
+			}
+		}
+		msgs = append(msgs, fmt.Sprintf("%s() is applied to %q, which does not look like a counter", call.Func.Name, vs.Name))
+		return nil
+	})
+	return msgs
+}
+
+func checkRangeTooShort(expr parser.Expr, interval time.Duration) []string {
+	var msgs []string
+	report := func(what string, r time.Duration) {
+		if r > 0 && r < 2*interval {
+			msgs = append(msgs, fmt.Sprintf("%s range [%s] is shorter than twice the group interval (%s)", what, model.Duration(r), model.Duration(interval)))
+		}
+	}
+	parser.Inspect(expr, func(node parser.Node, _ []parser.Node) error {
+		switch n := node.(type) {
+		case *parser.MatrixSelector:
+			// Ranges computed from expressions (RangeExpr) are not known before evaluation.
+			if n.RangeExpr == nil {
+				report("selector", n.Range)
+			}
+		case *parser.SubqueryExpr:
+			if n.RangeExpr == nil {
+				report("subquery", n.Range)
+			}
+		}
+		return nil
+	})
+	return msgs
+}
+
+func checkAggregationDropsLabels(expr parser.Expr, group RuleGroup, rule Rule) []string {
+	if rule.Alert == "" {
+		return nil
+	}
+
+	referenced := make(map[string]bool)
+	for _, text := range rule.Annotations {
+		for _, re := range templateLabelRegexps {
+			for _, m := range re.FindAllStringSubmatch(text, -1) {
+				referenced[m[1]] = true
+			}
+		}
+	}
+
+	var msgs []string
+	for label := range referenced {
+		// Labels set on the rule or the group are always present on the alert.
+		if _, ok := rule.Labels[label]; ok {
+			continue
+		}
+		if _, ok := group.Labels[label]; ok {
+			continue
+		}
+		if strings.HasPrefix(label, "__") {
+			continue
+		}
+		if promQLDropsLabel(expr, label) {
+			msgs = append(msgs, fmt.Sprintf("annotations reference label %q, which is not present in the expression result", label))
+		}
+	}
+	sort.Strings(msgs)
+	return msgs
+}
+
+// promQLDropsLabel reports whether label is certainly absent from the series returned by
+// expr. Unknown constructs are assumed to keep their labels.
+func promQLDropsLabel(expr parser.Expr, label string) bool {
+	switch n := expr.(type) {
+	case *parser.ParenExpr:
+		return promQLDropsLabel(n.Expr, label)
+	case *parser.UnaryExpr:
+		return promQLDropsLabel(n.Expr, label)
+	case *parser.StepInvariantExpr:
+		return promQLDropsLabel(n.Expr, label)
+	case *parser.NumberLiteral, *parser.StringLiteral:
+		return true
+	case *parser.AggregateExpr:
+		switch n.Op {
+		case parser.TOPK, parser.BOTTOMK, parser.LIMITK, parser.LIMIT_RATIO:
+			return promQLDropsLabel(n.Expr, label)
+		case parser.COUNT_VALUES:
+			if s, ok := n.Param.(*parser.StringLiteral); ok && s.Val == label {
+				return false
+			}
+		}
+		if n.Without {
+			return contains(n.Grouping, label) || promQLDropsLabel(n.Expr, label)
+		}
+		return !contains(n.Grouping, label)
+	case *parser.BinaryExpr:
+		if n.VectorMatching == nil || n.LHS.Type() != parser.ValueTypeVector || n.RHS.Type() != parser.ValueTypeVector {
+			// Vector/scalar operations keep the labels of the vector side.
+			if n.LHS.Type() == parser.ValueTypeVector {
+				return promQLDropsLabel(n.LHS, label)
+			}
+			if n.RHS.Type() == parser.ValueTypeVector {
+				return promQLDropsLabel(n.RHS, label)
+			}
+			return true
+		}
+		if n.Op == parser.LOR {
+			return promQLDropsLabel(n.LHS, label) && promQLDropsLabel(n.RHS, label)
+		}
+		m := n.VectorMatching
+		if contains(m.Include, label) {
+			return false
+		}
+		if m.Card == parser.CardOneToOne && !n.Op.IsSetOperator() {
+			if m.On && !contains(m.MatchingLabels, label) {
+				return true
+			}
+			if !m.On && contains(m.MatchingLabels, label) {
+				return true
+			}
+		}
+		if m.Card == parser.CardOneToMany {
+			return promQLDropsLabel(n.RHS, label)
+		}
+		return promQLDropsLabel(n.LHS, label)
+	case *parser.Call:
+		switch n.Func.Name {
+		case "absent", "absent_over_time":
+			return !absentKeepsLabel(n, label)
+		case "vector", "time", "scalar", "pi":
+			return true
+		case "histogram_quantile", "histogram_fraction":
+			if label == "le" {
+				return true
+			}
+		case "label_replace", "label_join":
+			if len(n.Args) > 1 {
+				if dst, ok := n.Args[1].(*parser.StringLiteral); ok && dst.Val == label {
+					return false
+				}
+			}
+		}
+		for _, arg := range n.Args {
+			if arg.Type() == parser.ValueTypeVector || arg.Type() == parser.ValueTypeMatrix {
+				return promQLDropsLabel(arg, label)
+			}
+		}
+		return false
+	case *parser.MatrixSelector:
+		return promQLDropsLabel(n.VectorSelector, label)
+	case *parser.SubqueryExpr:
+		return promQLDropsLabel(n.Expr, label)
+	}
+	return false
+}
+
+// absentKeepsLabel reports whether absent() returns label: only the equality matchers of
+// a plain selector argument are kept.
+func absentKeepsLabel(call *parser.Call, label string) bool {
+	if len(call.Args) == 0 {
+		return false
+	}
+	arg := call.Args[0]
+	if ms, ok := arg.(*parser.MatrixSelector); ok {
+		arg = ms.VectorSelector
+	}
+	vs, ok := arg.(*parser.VectorSelector)
+	if !ok {
+		return false
+	}
+	for _, m := range vs.LabelMatchers {
+		if m.Name == label && m.Type == labels.MatchEqual {
+			return true
+		}
+	}
+	return false
+}
+
+func checkAbsent(expr parser.Expr, rule Rule) []string {
+	var msgs []string
+	parser.Inspect(expr, func(node parser.Node, _ []parser.Node) error {
+		switch n := node.(type) {
+		case *parser.Call:
+			if n.Func.Name != "absent" && n.Func.Name != "absent_over_time" {
+				return nil
+			}
+			if rule.Record != "" {
+				msgs = append(msgs, fmt.Sprintf("%s() in a recording rule only produces a series while the data is missing", n.Func.Name))
+			}
+			if len(n.Args) == 0 {
+				return nil
+			}
+			arg := n.Args[0]
+			if ms, ok := arg.(*parser.MatrixSelector); ok {
+				arg = ms.VectorSelector
+			}
+			if _, ok := arg.(*parser.VectorSelector); !ok {
+				msgs = append(msgs, fmt.Sprintf("%s() wraps %q, which is not a plain selector: the result has no labels and may never fire", n.Func.Name, n.Args[0].String()))
+			}
+		case *parser.BinaryExpr:
+			if !n.Op.IsComparisonOperator() {
+				return nil
+			}
+			for _, side := range []parser.Expr{n.LHS, n.RHS} {
+				if isAbsentCall(side) {
+					msgs = append(msgs, fmt.Sprintf("comparing the result of %q is redundant or never matches: absent() returns 1 or nothing", side.String()))
+				}
+			}
+		}
+		return nil
+	})
+	return msgs
+}
+
+func isAbsentCall(expr parser.Expr) bool {
+	for {
+		p, ok := expr.(*parser.ParenExpr)
+		if !ok {
+			break
+		}
+		expr = p.Expr
+	}
+	call, ok := expr.(*parser.Call)
+	return ok && (call.Func.Name == "absent" || call.Func.Name == "absent_over_time")
+}
+
+func checkRecordingRuleAggregation(expr parser.Expr, rule Rule) []string {
+	if rule.Record == "" || !validRecordingRuleNameFormat(rule.Record) {
+		return nil
+	}
+	for {
+		p, ok := expr.(*parser.ParenExpr)
+		if !ok {
+			break
+		}
+		expr = p.Expr
+	}
+	agg, ok := expr.(*parser.AggregateExpr)
+	if !ok {
+		return nil
+	}
+	// Aggregations are often left out of the name (path:requests:rate5m for a sum of
+	// rates), so only a name mentioning another aggregation is reported.
+	op := agg.Op.String()
+	var named []string
+	for _, part := range strings.Split(strings.Split(rule.Record, ":")[2], "_") {
+		if part == op {
+			return nil
+		}
+		if aggregationNames[part] {
+			named = append(named, part)
+		}
+	}
+	if len(named) == 0 {
+		return nil
+	}
+	return []string{fmt.Sprintf("recording rule name %q mentions %s but its expression aggregates with %q", rule.Record, strings.Join(named, ", "), op)}
+}
@@ -0,0 +1,119 @@
+package mimir
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPromQLCheckRuleGroups(t *testing.T) {
+	cases := []struct {
+		name  string
+		group RuleGroup
+		want  []string
+	}{
+		{
+			name: "rate on gauge",
+			group: RuleGroup{Name: "g", Rules: []Rule{
+				{Alert: "A", Expr: `rate(node_memory_free_bytes[5m]) > 0`},
+				{Alert: "B", Expr: `rate(http_requests_total[5m]) > 0`},
+			}},
+			want: []string{`promql/rate_on_gauge: rate() is applied to "node_memory_free_bytes", which does not look like a counter`},
+		},
+		{
+			name: "range shorter than twice the interval",
+			group: RuleGroup{Name: "g", Interval: "2m", Rules: []Rule{
+				{Record: "job:http_requests:rate3m", Expr: `sum by (job) (rate(http_requests_total[3m]))`},
+				{Record: "job:http_requests:rate5m", Expr: `sum by (job) (rate(http_requests_total[5m]))`},
+			}},
+			want: []string{`promql/range_too_short: selector range [3m] is shorter than twice the group interval (2m)`},
+		},
+		{
+			name: "aggregation drops labels used in annotations",
+			group: RuleGroup{Name: "g", Labels: map[string]string{"team": "a"}, Rules: []Rule{
+				{
+					Alert:       "A",
+					Expr:        `sum by (job) (up) == 0`,
+					Labels:      map[string]string{"severity": "page"},
+					Annotations: map[string]string{"summary": `{{ $labels.job }} {{ $labels.instance }} {{ $labels.severity }} {{ $labels.team }}`},
+				},
+				{
+					Alert:       "B",
+					Expr:        `up == 0`,
+					Annotations: map[string]string{"summary": `{{ $labels.instance }}`},
+				},
+				{
+					Alert:       "C",
+					Expr:        `count without (instance) (up) == 0`,
+					Annotations: map[string]string{"summary": `{{ .Labels.instance }}`},
+				},
+			}},
+			want: []string{
+				`promql/aggregation_drops_labels: annotations reference label "instance", which is not present in the expression result`,
+				`promql/aggregation_drops_labels: annotations reference label "instance", which is not present in the expression result`,
+			},
+		},
+		{
+			name: "absent misuse",
+			group: RuleGroup{Name: "g", Rules: []Rule{
+				{Alert: "A", Expr: `absent(up{job="api"})`, Annotations: map[string]string{"summary": `{{ $labels.job }}`}},
+				{Alert: "B", Expr: `absent(sum(up))`},
+				{Alert: "C", Expr: `absent(up) == 1`},
+				{Alert: "D", Expr: `absent(up{job=~"api"})`, Annotations: map[string]string{"summary": `{{ $labels.job }}`}},
+			}},
+			want: []string{
+				`promql/absent: absent() wraps "sum(up)", which is not a plain selector: the result has no labels and may never fire`,
+				`promql/absent: comparing the result of "absent(up)" is redundant or never matches: absent() returns 1 or nothing`,
+				`promql/aggregation_drops_labels: annotations reference label "job", which is not present in the expression result`,
+			},
+		},
+		{
+			name: "recording rule name and aggregation",
+			group: RuleGroup{Name: "g", Rules: []Rule{
+				{Record: "job:up:sum", Expr: `avg by (job) (up)`},
+				{Record: "job:up:avg", Expr: `avg by (job) (up)`},
+				{Record: "path:http_requests:rate5m", Expr: `sum by (path) (rate(http_requests_total[5m]))`},
+			}},
+			want: []string{`promql/recording_rule_aggregation: recording rule name "job:up:sum" mentions sum but its expression aggregates with "avg"`},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var got []string
+			for _, f := range promQLCheckRuleGroups(RuleGroups{Groups: []RuleGroup{c.group}}, defaultPromQLChecks()) {
+				if f.Severity != ruleLintSeverityWarning {
+					t.Errorf("finding %s is not a warning", f)
+				}
+				got = append(got, f.Policy+": "+f.Message)
+			}
+			if strings.Join(got, "\n") != strings.Join(c.want, "\n") {
+				t.Errorf("unexpected findings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(c.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestExpandPromQLChecks(t *testing.T) {
+	checks := expandPromQLChecks(nil)
+	for _, c := range promQLChecks {
+		if !checks[c.name] {
+			t.Errorf("check %s should be enabled by default", c.name)
+		}
+	}
+
+	checks = expandPromQLChecks([]interface{}{map[string]interface{}{
+		promQLCheckRateOnGauge:              false,
+		promQLCheckRangeTooShort:            true,
+		promQLCheckAggregationDropsLabels:   true,
+		promQLCheckAbsent:                   true,
+		promQLCheckRecordingRuleAggregation: true,
+	}})
+	if checks[promQLCheckRateOnGauge] || !checks[promQLCheckAbsent] {
+		t.Errorf("unexpected checks %v", checks)
+	}
+
+	group := RuleGroup{Name: "g", Rules: []Rule{{Alert: "A", Expr: `rate(node_memory_free_bytes[5m]) > 0`}}}
+	if findings := promQLCheckRuleGroups(RuleGroups{Groups: []RuleGroup{group}}, checks); len(findings) != 0 {
+		t.Errorf("disabled check reported %v", findings)
+	}
+}
@@ -2,10 +2,13 @@ package mimir
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 var (
@@ -49,6 +52,30 @@ func Provider(version string) func() *schema.Provider {
 					DefaultFunc: schema.EnvDefaultFunc("MIMIR_DISTRIBUTOR_URI", nil),
 					Description: "mimir distributor base url",
 				},
+				"query_frontend_uri": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("MIMIR_QUERY_FRONTEND_URI", nil),
+					Description: "mimir query-frontend base url, including the Prometheus HTTP prefix (e.g. `http://localhost:8080/prometheus`)",
+				},
+				"ingester_uri": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("MIMIR_INGESTER_URI", nil),
+					Description: "mimir ingester base url, used to read the ingester ring. Defaults to `uri`.",
+				},
+				"store_gateway_uri": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("MIMIR_STORE_GATEWAY_URI", nil),
+					Description: "mimir store-gateway base url, used to read the store-gateway ring. Defaults to `uri`.",
+				},
+				"compactor_uri": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("MIMIR_COMPACTOR_URI", nil),
+					Description: "mimir compactor base url, used to read the compactor ring and to delete tenants. Defaults to `uri`.",
+				},
 				"org_id": {
 					Type:        schema.TypeString,
 					Required:    true,
@@ -61,6 +88,26 @@ func Provider(version string) func() *schema.Provider {
 					DefaultFunc: schema.EnvDefaultFunc("MIMIR_TOKEN", nil),
 					Description: "When set, will use this token for Bearer auth to the API.",
 				},
+				"token_file": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("MIMIR_TOKEN_FILE", nil),
+					Description: "When set, will read the Bearer token from this file before each request, so a rotated token is picked up.",
+				},
+				"oauth2": {
+					Type:        schema.TypeList,
+					Optional:    true,
+					MaxItems:    1,
+					Description: "When set, will use OAuth2 client credentials to get a Bearer token, refreshed before it expires.",
+					Elem:        oauth2Schema(),
+				},
+				"sigv4": {
+					Type:        schema.TypeList,
+					Optional:    true,
+					MaxItems:    1,
+					Description: "When set, will sign every request with AWS Signature Version 4, e.g. for Mimir exposed through AWS API Gateway.",
+					Elem:        sigv4Schema(),
+				},
 				"username": {
 					Type:        schema.TypeString,
 					Optional:    true,
@@ -89,7 +136,7 @@ func Provider(version string) func() *schema.Provider {
 					Type:        schema.TypeString,
 					Optional:    true,
 					DefaultFunc: schema.EnvDefaultFunc("MIMIR_TLS_CERT", nil),
-					Description: "Client cert (filepath or inline) for TLS client authentication.",
+					Description: "Client cert (filepath or inline) for TLS client authentication. Files are re-read when they change, so a rotated certificate is used without restarting Terraform.",
 				},
 				"key": {
 					Type:        schema.TypeString,
@@ -101,7 +148,13 @@ func Provider(version string) func() *schema.Provider {
 					Type:        schema.TypeString,
 					Optional:    true,
 					DefaultFunc: schema.EnvDefaultFunc("MIMIR_TLS_CA", nil),
-					Description: "Client ca (filepath or inline) for TLS client authentication.",
+					Description: "Client ca (filepath, directory of PEM files or inline) to verify the server certificate. Files are re-read when they change.",
+				},
+				"include_system_ca": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("MIMIR_TLS_INCLUDE_SYSTEM_CA", false),
+					Description: "Trust the system CAs in addition to `ca`, instead of only `ca`.",
 				},
 				"headers": {
 					Type:        schema.TypeMap,
@@ -119,7 +172,7 @@ func Provider(version string) func() *schema.Provider {
 					Type:        schema.TypeBool,
 					Optional:    true,
 					DefaultFunc: schema.EnvDefaultFunc("MIMIR_DEBUG", false),
-					Description: "Enable debug mode to trace requests executed.",
+					Description: "Log the headers and bodies of the requests and responses at the DEBUG level (`TF_LOG=DEBUG`), with credentials and Alertmanager secrets masked.",
 				},
 				"format_promql_expr": {
 					Type:        schema.TypeBool,
@@ -152,6 +205,13 @@ func Provider(version string) func() *schema.Provider {
 					DefaultFunc: schema.EnvDefaultFunc("MIMIR_RULE_GROUP_READ_RETRY_AFTER_CHANGE", 3),
 					Description: "Max retries to read the rule group after a change.",
 				},
+				"parallelism": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					DefaultFunc:  schema.EnvDefaultFunc("MIMIR_PARALLELISM", 1),
+					Description:  "Default number of rule groups a `mimir_rules` resource reads, creates, updates or deletes concurrently.",
+					ValidateFunc: validation.IntAtLeast(1),
+				},
 				"alertmanager_read_delay_after_change": {
 					Type:         schema.TypeString,
 					Optional:     true,
@@ -165,29 +225,69 @@ func Provider(version string) func() *schema.Provider {
 					DefaultFunc: schema.EnvDefaultFunc("MIMIR_ALERTMANAGER_READ_RETRY_AFTER_CHANGE", 3),
 					Description: "Max retries to read the alertmanager config after a change.",
 				},
+				"detect_version": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					DefaultFunc: schema.EnvDefaultFunc("MIMIR_DETECT_VERSION", false),
+					Description: "Probe the Mimir version (`/api/v1/status/buildinfo` on the ruler uri) at configure time, and fail the plan when a resource uses an attribute the cluster does not support, or warn on apply when older versions only drop it (rule group `labels`).",
+				},
+				"promql_checks": {
+					Type:        schema.TypeList,
+					Optional:    true,
+					MaxItems:    1,
+					Description: "Toggle the semantic checks run on rule expressions at plan time. They only report warnings; all checks are enabled by default.",
+					Elem:        promQLChecksSchema(),
+				},
+				"rule_lint": {
+					Type:        schema.TypeList,
+					Optional:    true,
+					Description: "Convention policies checked at plan time on the rules of `mimir_rule_group_alerting`, `mimir_rule_group_recording` and `mimir_rules`. Each policy may be restricted to some namespaces and either fails the plan or only warns.",
+					Elem:        ruleLintPolicySchema(),
+				},
 			},
 			DataSourcesMap: map[string]*schema.Resource{
-				"mimir_alertmanager_config":      dataSourcemimirAlertmanagerConfig(),
-				"mimir_rule_group_alerting":      dataSourcemimirRuleGroupAlerting(),
-				"mimir_rule_group_recording":     dataSourcemimirRuleGroupRecording(),
-				"mimir_distributor_tenant_stats": dataSourcemimirDistributorTenantStats(),
+				"mimir_alertmanager_config":       dataSourcemimirAlertmanagerConfig(),
+				"mimir_rule_group_alerting":       dataSourcemimirRuleGroupAlerting(),
+				"mimir_rule_group_recording":      dataSourcemimirRuleGroupRecording(),
+				"mimir_distributor_tenant_stats":  dataSourcemimirDistributorTenantStats(),
+				"mimir_rules_lint":                dataSourcemimirRulesLint(),
+				"mimir_mixin":                     dataSourcemimirMixin(),
+				"mimir_build_info":                dataSourcemimirBuildInfo(),
+				"mimir_query":                     dataSourcemimirQuery(),
+				"mimir_cardinality_label_names":   dataSourcemimirCardinalityLabelNames(),
+				"mimir_cardinality_label_values":  dataSourcemimirCardinalityLabelValues(),
+				"mimir_cardinality_active_series": dataSourcemimirCardinalityActiveSeries(),
+				"mimir_tenant_limits":             dataSourcemimirTenantLimits(),
+				"mimir_ring_status":               dataSourcemimirRingStatus(),
+				"mimir_services_status":           dataSourcemimirServicesStatus(),
 			},
 			ResourcesMap: map[string]*schema.Resource{
-				"mimir_alertmanager_config":  resourcemimirAlertmanagerConfig(),
-				"mimir_rule_group_alerting":  resourcemimirRuleGroupAlerting(),
-				"mimir_rule_group_recording": resourcemimirRuleGroupRecording(),
-				"mimir_rules":                resourceMimirRules(),
+				"mimir_alertmanager_config":     resourcemimirAlertmanagerConfig(),
+				"mimir_rule_group_alerting":     resourcemimirRuleGroupAlerting(),
+				"mimir_rule_group_recording":    resourcemimirRuleGroupRecording(),
+				"mimir_rules":                   resourceMimirRules(),
+				"mimir_series_deletion_request": resourcemimirSeriesDeletionRequest(),
+				"mimir_tenant_deletion":         resourcemimirTenantDeletion(),
 			},
 		}
+		for _, name := range componentNames {
+			p.Schema[name] = &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: fmt.Sprintf("Connection settings used instead of the provider ones for the requests sent to the %s. Unset settings are inherited from the provider; setting any authentication method replaces the provider authentication.", strings.ReplaceAll(name, "_", "-")),
+				Elem:        componentSchema(),
+			}
+		}
 		p.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 			p.UserAgent("terraform-provider-mimir", version)
-			return providerConfigure(version, p, d)
+			return providerConfigure(ctx, version, p, d)
 		}
 		return p
 	}
 }
 
-func providerConfigure(version string, p *schema.Provider, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+func providerConfigure(ctx context.Context, version string, p *schema.Provider, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 	headers := make(map[string]string)
 	if initHeaders := d.Get("headers"); initHeaders != nil {
 		for k, v := range initHeaders.(map[string]interface{}) {
@@ -201,21 +301,28 @@ func providerConfigure(version string, p *schema.Provider, d *schema.ResourceDat
 	headers["User-Agent"] = p.UserAgent("terraform-provider-mimir", version)
 
 	opt := &apiClientOpt{
-		token:           d.Get("token").(string),
-		username:        d.Get("username").(string),
-		password:        d.Get("password").(string),
-		proxyURL:        d.Get("proxy_url").(string),
-		cert:            d.Get("cert").(string),
-		key:             d.Get("key").(string),
-		ca:              d.Get("ca").(string),
-		insecure:        d.Get("insecure").(bool),
-		uri:             d.Get("uri").(string),
-		rulerURI:        d.Get("ruler_uri").(string),
-		alertmanagerURI: d.Get("alertmanager_uri").(string),
-		distributorURI:  d.Get("distributor_uri").(string),
-		headers:         headers,
-		timeout:         d.Get("timeout").(int),
-		debug:           d.Get("debug").(bool),
+		token:            d.Get("token").(string),
+		tokenFile:        d.Get("token_file").(string),
+		oauth2:           expandOAuth2(d.Get("oauth2").([]interface{})),
+		username:         d.Get("username").(string),
+		password:         d.Get("password").(string),
+		proxyURL:         d.Get("proxy_url").(string),
+		cert:             d.Get("cert").(string),
+		key:              d.Get("key").(string),
+		ca:               d.Get("ca").(string),
+		includeSystemCA:  d.Get("include_system_ca").(bool),
+		insecure:         d.Get("insecure").(bool),
+		uri:              d.Get("uri").(string),
+		rulerURI:         d.Get("ruler_uri").(string),
+		alertmanagerURI:  d.Get("alertmanager_uri").(string),
+		distributorURI:   d.Get("distributor_uri").(string),
+		queryFrontendURI: d.Get("query_frontend_uri").(string),
+		ingesterURI:      d.Get("ingester_uri").(string),
+		storeGatewayURI:  d.Get("store_gateway_uri").(string),
+		compactorURI:     d.Get("compactor_uri").(string),
+		headers:          headers,
+		timeout:          d.Get("timeout").(int),
+		debug:            d.Get("debug").(bool),
 	}
 
 	enablePromQLExprFormat = d.Get("format_promql_expr").(bool)
@@ -228,6 +335,45 @@ func providerConfigure(version string, p *schema.Provider, d *schema.ResourceDat
 	ruleGroupReadRetryAfterChange = d.Get("rule_group_read_retry_after_change").(int)
 	alertmanagerReadRetryAfterChange = d.Get("alertmanager_read_retry_after_change").(int)
 
+	enabledPromQLChecks = expandPromQLChecks(d.Get("promql_checks").([]interface{}))
+
+	var err error
+	ruleLintPolicies, err = expandRuleLintPolicies(d.Get("rule_lint").([]interface{}))
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+
+	opt.sigv4, err = expandSigV4(d.Get("sigv4").([]interface{}))
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+
+	opt.components = make(map[string]*apiClientOpt)
+	for _, name := range componentNames {
+		componentOpt, err := expandComponentOpt(d.Get(name).([]interface{}), componentInsecure(d, name), opt)
+		if err != nil {
+			return nil, diag.FromErr(fmt.Errorf("%s block: %v", name, err))
+		}
+		if componentOpt != nil {
+			opt.components[name] = componentOpt
+		}
+	}
+
 	client, err := NewAPIClient(opt)
-	return client, diag.FromErr(err)
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	client.rulesParallelism = d.Get("parallelism").(int)
+
+	var diags diag.Diagnostics
+	if d.Get("detect_version").(bool) {
+		if err := client.detectVersion(ctx); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "Unable to detect the Mimir version",
+				Detail:   fmt.Sprintf("Version checks are disabled: %v", err),
+			})
+		}
+	}
+	return client, diags
 }
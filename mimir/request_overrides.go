@@ -0,0 +1,99 @@
+package mimir
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// requestHeadersSchema is the request_headers attribute of the resources and data sources
+// sending requests; kind is "resource" or "data source".
+func requestHeadersSchema(kind string) *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeMap,
+		Optional:     true,
+		Sensitive:    true,
+		Elem:         &schema.Schema{Type: schema.TypeString},
+		ValidateFunc: validateRequestHeaders,
+		Description:  fmt.Sprintf("A map of header names and values to set on the requests of this %s, merged over the provider and component `headers`, e.g. the routing header or credentials of a tenant at the gateway. `X-Scope-OrgID` is set from `org_id` instead.", kind),
+	}
+}
+
+func validateRequestHeaders(v interface{}, k string) (ws []string, errors []error) {
+	for name := range v.(map[string]interface{}) {
+		if http.CanonicalHeaderKey(name) == http.CanonicalHeaderKey("X-Scope-OrgID") {
+			errors = append(errors, fmt.Errorf("%q cannot set %s, use org_id instead", k, name))
+		}
+	}
+	return
+}
+
+// requestAuthSchema is the auth block of the resources and data sources sending requests;
+// kind is "resource" or "data source".
+func requestAuthSchema(kind string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: fmt.Sprintf("Authentication used for the requests of this %s instead of the provider and component one. Not applied when requests are signed with `sigv4`.", kind),
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"token": {
+					Type:          schema.TypeString,
+					Optional:      true,
+					Sensitive:     true,
+					ConflictsWith: []string{"auth.0.username"},
+					Description:   "When set, will use this token for Bearer auth.",
+				},
+				"username": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					RequiredWith: []string{"auth.0.password"},
+					Description:  "When set, will use this username for BASIC auth.",
+				},
+				"password": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Sensitive:    true,
+					RequiredWith: []string{"auth.0.username"},
+					Description:  "When set, will use this password for BASIC auth.",
+				},
+			},
+		},
+	}
+}
+
+// resourceGetter reads the configuration of a resource, as *schema.ResourceData at apply
+// and *schema.ResourceDiff at plan.
+type resourceGetter interface {
+	GetOk(key string) (interface{}, bool)
+}
+
+// forResource returns a copy of the client sending the request_headers and auth of d,
+// or the client itself when d sets neither.
+func (client *apiClient) forResource(d resourceGetter) *apiClient {
+	headers := make(map[string]string)
+	if v, ok := d.GetOk("request_headers"); ok {
+		for k, v := range v.(map[string]interface{}) {
+			headers[k] = v.(string)
+		}
+	}
+	var auth *credentials
+	if v, ok := d.GetOk("auth"); ok && len(v.([]interface{})) > 0 && v.([]interface{})[0] != nil {
+		data := v.([]interface{})[0].(map[string]interface{})
+		auth = &credentials{
+			token:    data["token"].(string),
+			username: data["username"].(string),
+			password: data["password"].(string),
+		}
+	}
+	if len(headers) == 0 && auth == nil {
+		return client
+	}
+
+	c := *client
+	c.requestHeaders = headers
+	c.requestAuth = auth
+	return &c
+}
@@ -0,0 +1,90 @@
+package mimir
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestForResource(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("Authorization") + "|" + r.Header.Get("X-Gateway-Tenant") + "|" + r.Header.Get("X-Scope-OrgID")))
+	}))
+	defer srv.Close()
+
+	client, err := NewAPIClient(&apiClientOpt{
+		uri:      srv.URL,
+		username: "provider",
+		password: "pass",
+		headers:  map[string]string{"X-Scope-OrgID": "default", "X-Gateway-Tenant": "default"},
+		timeout:  2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := map[string]*schema.Schema{
+		"request_headers": requestHeadersSchema("resource"),
+		"auth":            requestAuthSchema("resource"),
+	}
+
+	tests := []struct {
+		name string
+		raw  map[string]interface{}
+		want string
+	}{
+		{
+			name: "provider settings",
+			raw:  map[string]interface{}{},
+			want: "Basic cHJvdmlkZXI6cGFzcw==|default|default",
+		},
+		{
+			name: "request headers",
+			raw: map[string]interface{}{
+				"request_headers": map[string]interface{}{"X-Gateway-Tenant": "team-a", "Authorization": "Bearer team-a"},
+			},
+			want: "Bearer team-a|team-a|default",
+		},
+		{
+			name: "auth token",
+			raw: map[string]interface{}{
+				"auth": []interface{}{map[string]interface{}{"token": "team-b"}},
+			},
+			want: "Bearer team-b|default|default",
+		},
+		{
+			name: "auth basic",
+			raw: map[string]interface{}{
+				"auth": []interface{}{map[string]interface{}{"username": "team-c", "password": "pass"}},
+			},
+			want: "Basic dGVhbS1jOnBhc3M=|default|default",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, s, tt.raw)
+			got, err := client.forResource(d).sendRequest(context.Background(), "", "GET", "/", "", map[string]string{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if c := client.forResource(schema.TestResourceDataRaw(t, s, map[string]interface{}{})); c != client {
+		t.Error("expected the provider client without overrides")
+	}
+}
+
+func TestValidateRequestHeaders(t *testing.T) {
+	if _, errs := validateRequestHeaders(map[string]interface{}{"X-Gateway-Tenant": "a"}, "request_headers"); len(errs) != 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	if _, errs := validateRequestHeaders(map[string]interface{}{"x-scope-orgid": "a"}, "request_headers"); len(errs) != 1 {
+		t.Errorf("expected X-Scope-OrgID to be rejected, got %v", errs)
+	}
+}
@@ -18,6 +18,12 @@ func resourcemimirAlertmanagerConfig() *schema.Resource {
 		ReadContext:   resourcemimirAlertmanagerConfigRead,
 		UpdateContext: resourcemimirAlertmanagerConfigUpdate,
 		DeleteContext: resourcemimirAlertmanagerConfigDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
 		Importer: &schema.ResourceImporter{
 			StateContext: resourcemimirAlertmanagerConfigImport,
 		},
@@ -26,7 +32,7 @@ func resourcemimirAlertmanagerConfig() *schema.Resource {
 }
 
 func resourcemimirAlertmanagerConfigCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*apiClient)
+	client := meta.(*apiClient).forResource(d)
 	orgID := d.Get("org_id").(string)
 
 	if !overwriteAlertmanagerConfig {
@@ -35,7 +41,7 @@ func resourcemimirAlertmanagerConfigCreate(ctx context.Context, d *schema.Resour
 		if orgID != "" {
 			headers["X-Scope-OrgID"] = orgID
 		}
-		resp, err := client.sendRequest("alertmanager", "GET", apiAlertsPath, "", headers)
+		resp, err := client.sendRequest(ctx, "alertmanager", "GET", apiAlertsPath, "", headers)
 		baseMsg := "Cannot read alertmanager config"
 		err = handleHTTPError(err, baseMsg)
 		if err != nil {
@@ -56,7 +62,7 @@ func resourcemimirAlertmanagerConfigCreate(ctx context.Context, d *schema.Resour
 		}
 	}
 
-	_, err := alertmanagerConfigCreateUpdate(client, d, apiAlertsPath)
+	_, err := alertmanagerConfigCreateUpdate(ctx, client, d, apiAlertsPath)
 	baseMsg := "Cannot create alertmanager config"
 	err = handleHTTPError(err, baseMsg)
 	if err != nil {
@@ -74,7 +80,9 @@ func resourcemimirAlertmanagerConfigCreate(ctx context.Context, d *schema.Resour
 		result := resourcemimirAlertmanagerConfigRead(ctx, d, meta)
 		if len(result) > 0 && !result.HasError() {
 			log.Printf("[WARN] Alertmanager config previously created not found (%d/3)", i)
-			time.Sleep(alertmanagerReadDelayAfterChangeDuration)
+			if err := sleepContext(ctx, alertmanagerReadDelayAfterChangeDuration); err != nil {
+				return diag.FromErr(err)
+			}
 			continue
 		}
 		return result
@@ -85,7 +93,7 @@ func resourcemimirAlertmanagerConfigCreate(ctx context.Context, d *schema.Resour
 func resourcemimirAlertmanagerConfigRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	orgID := d.Get("org_id").(string)
 	var diags diag.Diagnostics
-	resp, err := alertmanagerConfigRead(meta, orgID)
+	resp, err := alertmanagerConfigRead(ctx, meta.(*apiClient).forResource(d), orgID)
 	if err != nil {
 		if d.IsNewResource() && strings.Contains(err.Error(), "response code '404'") {
 			diags = append(diags, diag.Diagnostic{
@@ -154,26 +162,28 @@ func resourcemimirAlertmanagerConfigRead(ctx context.Context, d *schema.Resource
 }
 
 func resourcemimirAlertmanagerConfigUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*apiClient)
-	_, err := alertmanagerConfigCreateUpdate(client, d, apiAlertsPath)
+	client := meta.(*apiClient).forResource(d)
+	_, err := alertmanagerConfigCreateUpdate(ctx, client, d, apiAlertsPath)
 	baseMsg := "Cannot update alertmanager config"
 	err = handleHTTPError(err, baseMsg)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 	// Add time delay before read to wait the event change notification propagation to finish
-	time.Sleep(alertmanagerReadDelayAfterChangeDuration)
+	if err := sleepContext(ctx, alertmanagerReadDelayAfterChangeDuration); err != nil {
+		return diag.FromErr(err)
+	}
 	return resourcemimirAlertmanagerConfigRead(ctx, d, meta)
 }
 
 func resourcemimirAlertmanagerConfigDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*apiClient)
+	client := meta.(*apiClient).forResource(d)
 	orgID := d.Get("org_id").(string)
 	headers := make(map[string]string)
 	if orgID != "" {
 		headers["X-Scope-OrgID"] = orgID
 	}
-	_, err := client.sendRequest("alertmanager", "DELETE", apiAlertsPath, "", headers)
+	_, err := client.sendRequest(ctx, "alertmanager", "DELETE", apiAlertsPath, "", headers)
 	if err != nil {
 		return diag.FromErr(fmt.Errorf(
 			"cannot delete alertmanager config from %s: %v",
@@ -184,10 +194,12 @@ func resourcemimirAlertmanagerConfigDelete(ctx context.Context, d *schema.Resour
 	// Retry read as mimir api could return a 200 status code but the alertmanager config still exist because of the event change notification propagation latency.
 	// Add delay of <alertmanagerReadDelayAfterChange> * time.Second) between each retry with a <alertmanagerReadRetryAfterChange> max retries.
 	for i := 1; i <= alertmanagerReadRetryAfterChange; i++ {
-		_, err := alertmanagerConfigRead(meta, orgID)
+		_, err := alertmanagerConfigRead(ctx, client, orgID)
 		if err == nil {
 			log.Printf("[WARN] Alertmanager config previously deleted still exist (%d/3)", i)
-			time.Sleep(alertmanagerReadDelayAfterChangeDuration)
+			if err := sleepContext(ctx, alertmanagerReadDelayAfterChangeDuration); err != nil {
+				return diag.FromErr(err)
+			}
 			continue
 		} else if strings.Contains(err.Error(), "response code '404'") {
 			break
@@ -198,18 +210,18 @@ func resourcemimirAlertmanagerConfigDelete(ctx context.Context, d *schema.Resour
 	return diag.Diagnostics{}
 }
 
-func alertmanagerConfigRead(meta interface{}, orgID string) (string, error) {
+func alertmanagerConfigRead(ctx context.Context, meta interface{}, orgID string) (string, error) {
 	client := meta.(*apiClient)
 	headers := make(map[string]string)
 	if orgID != "" {
 		headers["X-Scope-OrgID"] = orgID
 	}
-	resp, err := client.sendRequest("alertmanager", "GET", apiAlertsPath, "", headers)
+	resp, err := client.sendRequest(ctx, "alertmanager", "GET", apiAlertsPath, "", headers)
 	baseMsg := "Cannot read alertmanager config"
 	return resp, handleHTTPError(err, baseMsg)
 }
 
-func alertmanagerConfigCreateUpdate(client *apiClient, d *schema.ResourceData, path string) (string, error) {
+func alertmanagerConfigCreateUpdate(ctx context.Context, client *apiClient, d *schema.ResourceData, path string) (string, error) {
 	headers := map[string]string{"Content-Type": contentTypeYAML}
 	orgID := d.Get("org_id").(string)
 	if orgID != "" {
@@ -233,7 +245,7 @@ func alertmanagerConfigCreateUpdate(client *apiClient, d *schema.ResourceData, p
 
 	dataBytes, _ := yaml.Marshal(&alertmanagerUserConf)
 
-	resp, err := client.sendRequest("alertmanager", "POST", path, string(dataBytes), headers)
+	resp, err := client.sendRequest(ctx, "alertmanager", "POST", path, string(dataBytes), headers)
 
 	return resp, err
 }
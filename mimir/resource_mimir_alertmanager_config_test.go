@@ -1,6 +1,7 @@
 package mimir
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -34,7 +35,7 @@ func testAccCheckMimirAlertmanagerConfigExists(n string, client *apiClient) reso
 		}
 
 		/* Make a throw-away API object to read from the API */
-		_, err := client.sendRequest("alertmanager", "GET", apiAlertsPath, "", headers)
+		_, err := client.sendRequest(context.Background(), "alertmanager", "GET", apiAlertsPath, "", headers)
 		if err != nil {
 			return err
 		}
@@ -58,7 +59,7 @@ func testAccCheckMimirAlertmanagerConfigDestroy(s *terraform.State) error {
 		if orgID != "" {
 			headers["X-Scope-OrgID"] = orgID
 		}
-		_, err := client.sendRequest("alertmanager", "GET", apiAlertsPath, "", headers)
+		_, err := client.sendRequest(context.Background(), "alertmanager", "GET", apiAlertsPath, "", headers)
 		// If the error is equivalent to 404 not found, the widget is destroyed.
 		// Otherwise return the error
 		if !strings.Contains(err.Error(), "not found") {
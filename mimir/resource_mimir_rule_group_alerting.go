@@ -18,6 +18,12 @@ func resourcemimirRuleGroupAlerting() *schema.Resource {
 		ReadContext:   resourcemimirRuleGroupAlertingRead,
 		UpdateContext: resourcemimirRuleGroupAlertingUpdate,
 		DeleteContext: resourcemimirRuleGroupAlertingDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -97,14 +103,14 @@ func resourcemimirRuleGroupAlerting() *schema.Resource {
 						},
 						"annotations": {
 							Type:         schema.TypeMap,
-							Description:  "Annotations to add to each alert.",
+							Description:  "Annotations to add to each alert. Values are validated as alert templates.",
 							Optional:     true,
 							Elem:         &schema.Schema{Type: schema.TypeString},
 							ValidateFunc: validateAnnotations,
 						},
 						labelsKey: {
 							Type:         schema.TypeMap,
-							Description:  "Labels to add or overwrite for each alert.",
+							Description:  "Labels to add or overwrite for each alert. Values are validated as alert templates.",
 							Optional:     true,
 							Elem:         &schema.Schema{Type: schema.TypeString},
 							ValidateFunc: validateLabels,
@@ -112,12 +118,32 @@ func resourcemimirRuleGroupAlerting() *schema.Resource {
 					},
 				},
 			},
+			"request_headers": requestHeadersSchema("resource"),
+			"auth":            requestAuthSchema("resource"),
 		}, /* End schema */
+		CustomizeDiff: func(ctx context.Context, diff *schema.ResourceDiff, v interface{}) error {
+
+			// Rules computed from other resources are only linted once known.
+			if !diff.NewValueKnown("rule") || !diff.NewValueKnown(labelsKey) {
+				return nil
+			}
+			ruleGroups := alertingRuleGroupLintInput(diff.Get)
+			if !ruleGroupsKnown(ruleGroups) {
+				return nil
+			}
+			group := ruleGroups.Groups[0]
+			for i, rule := range group.Rules {
+				if err := validateRuleTemplates(rule); err != nil {
+					return fmt.Errorf("rule %d (%s): %v", i, rule.Alert, err)
+				}
+			}
+			return ruleLintCheck(ctx, diff.Get(namespaceKey).(string), ruleGroups)
+		},
 	}
 }
 
 func resourcemimirRuleGroupAlertingCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*apiClient)
+	client := meta.(*apiClient).forResource(d)
 	name := d.Get("name").(string)
 	namespace := d.Get(namespaceKey).(string)
 	orgID := d.Get(orgIDKey).(string)
@@ -130,7 +156,7 @@ func resourcemimirRuleGroupAlertingCreate(ctx context.Context, d *schema.Resourc
 		if orgID != "" {
 			headers["X-Scope-OrgID"] = orgID
 		}
-		_, err := client.sendRequest("ruler", "GET", path, "", headers)
+		_, err := client.sendRequest(ctx, "ruler", "GET", path, "", headers)
 		baseMsg := fmt.Sprintf("Cannot create alerting rule group '%s' (namespace: %s) -", name, namespace)
 		err = handleHTTPError(err, baseMsg)
 		if err != nil {
@@ -162,13 +188,15 @@ func resourcemimirRuleGroupAlertingCreate(ctx context.Context, d *schema.Resourc
 	}
 
 	path := rulesNamespacePath(namespace)
-	_, err := client.sendRequest("ruler", "POST", path, string(data), headers)
+	_, err := client.sendRequest(ctx, "ruler", "POST", path, string(data), headers)
 	baseMsg := fmt.Sprintf("Cannot create alerting rule group '%s' (namespace: %s) -", name, namespace)
 	err = handleHTTPError(err, baseMsg)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 	d.SetId(buildRuleGroupID(orgID, namespace, name))
+	lintDiags := ruleLintWarnings(namespace, alertingRuleGroupLintInput(d.Get))
+	lintDiags = append(lintDiags, ruleGroupFeatureWarnings(meta, alertingRuleGroupLintInput(d.Get))...)
 
 	// Retry read as mimir api could return a 404 status code caused by the event change notification propagation.
 	// Add delay of <ruleGroupReadDelayAfterChange> * time.Second) between each retry with a <ruleGroupReadRetryAfterChange> max retries.
@@ -176,12 +204,14 @@ func resourcemimirRuleGroupAlertingCreate(ctx context.Context, d *schema.Resourc
 		result := resourcemimirRuleGroupAlertingRead(ctx, d, meta)
 		if len(result) > 0 && !result.HasError() {
 			log.Printf("[WARN] Alerting rule group previously created'%s' not found (%d/3)", name, i)
-			time.Sleep(ruleGroupReadDelayAfterChangeDuration)
+			if err := sleepContext(ctx, ruleGroupReadDelayAfterChangeDuration); err != nil {
+				return diag.FromErr(err)
+			}
 			continue
 		}
-		return result
+		return append(lintDiags, result...)
 	}
-	return resourcemimirRuleGroupAlertingRead(ctx, d, meta)
+	return append(lintDiags, resourcemimirRuleGroupAlertingRead(ctx, d, meta)...)
 }
 
 func resourcemimirRuleGroupAlertingRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -194,7 +224,7 @@ func resourcemimirRuleGroupAlertingRead(ctx context.Context, d *schema.ResourceD
 		return diag.FromErr(err)
 	}
 
-	jobraw, err := ruleGroupAlertingRead(meta, name, namespace, orgID)
+	jobraw, err := ruleGroupAlertingRead(ctx, meta.(*apiClient).forResource(d), name, namespace, orgID)
 	if err != nil {
 		if d.IsNewResource() && strings.Contains(err.Error(), "response code '404'") {
 			diags = append(diags, diag.Diagnostic{
@@ -253,7 +283,7 @@ func resourcemimirRuleGroupAlertingRead(ctx context.Context, d *schema.ResourceD
 
 func resourcemimirRuleGroupAlertingUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	if d.HasChanges("rule", intervalKey, "source_tenants", labelsKey) {
-		client := meta.(*apiClient)
+		client := meta.(*apiClient).forResource(d)
 		name := d.Get("name").(string)
 		namespace := d.Get(namespaceKey).(string)
 		orgID := d.Get(orgIDKey).(string)
@@ -274,7 +304,7 @@ func resourcemimirRuleGroupAlertingUpdate(ctx context.Context, d *schema.Resourc
 		}
 
 		path := rulesNamespacePath(namespace)
-		_, err := client.sendRequest("ruler", "POST", path, string(data), headers)
+		_, err := client.sendRequest(ctx, "ruler", "POST", path, string(data), headers)
 		baseMsg := fmt.Sprintf("Cannot update alerting rule group '%s' (namespace: %s) -", name, namespace)
 
 		err = handleHTTPError(err, baseMsg)
@@ -283,12 +313,16 @@ func resourcemimirRuleGroupAlertingUpdate(ctx context.Context, d *schema.Resourc
 		}
 	}
 	// Add time delay before read to wait the event change notification propagation to finish
-	time.Sleep(ruleGroupReadDelayAfterChangeDuration)
-	return resourcemimirRuleGroupAlertingRead(ctx, d, meta)
+	if err := sleepContext(ctx, ruleGroupReadDelayAfterChangeDuration); err != nil {
+		return diag.FromErr(err)
+	}
+	lintDiags := ruleLintWarnings(d.Get(namespaceKey).(string), alertingRuleGroupLintInput(d.Get))
+	lintDiags = append(lintDiags, ruleGroupFeatureWarnings(meta, alertingRuleGroupLintInput(d.Get))...)
+	return append(lintDiags, resourcemimirRuleGroupAlertingRead(ctx, d, meta)...)
 }
 
 func resourcemimirRuleGroupAlertingDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*apiClient)
+	client := meta.(*apiClient).forResource(d)
 	name := d.Get("name").(string)
 	namespace := d.Get(namespaceKey).(string)
 	orgID := d.Get(orgIDKey).(string)
@@ -298,7 +332,7 @@ func resourcemimirRuleGroupAlertingDelete(ctx context.Context, d *schema.Resourc
 		headers["X-Scope-OrgID"] = orgID
 	}
 	path := rulesGroupPath(namespace, name)
-	_, err := client.sendRequest("ruler", "DELETE", path, "", headers)
+	_, err := client.sendRequest(ctx, "ruler", "DELETE", path, "", headers)
 	if err != nil {
 		return diag.FromErr(fmt.Errorf(
 			"cannot delete alerting rule group '%s' from %s: %v",
@@ -309,10 +343,12 @@ func resourcemimirRuleGroupAlertingDelete(ctx context.Context, d *schema.Resourc
 	// Retry read as mimir api could return a 200 status code but the rule group still exist because of the event change notification propagation latency.
 	// Add delay of <ruleGroupReadDelayAfterChange> * time.Second) between each retry with a <ruleGroupReadRetryAfterChange> max retries.
 	for i := 1; i <= ruleGroupReadRetryAfterChange; i++ {
-		_, err := ruleGroupAlertingRead(meta, name, namespace, orgID)
+		_, err := ruleGroupAlertingRead(ctx, client, name, namespace, orgID)
 		if err == nil {
 			log.Printf("[WARN] Alerting rule group previously deleted '%s' still exist (%d/3)", name, i)
-			time.Sleep(ruleGroupReadDelayAfterChangeDuration)
+			if err := sleepContext(ctx, ruleGroupReadDelayAfterChangeDuration); err != nil {
+				return diag.FromErr(err)
+			}
 			continue
 		} else if strings.Contains(err.Error(), "response code '404'") {
 			break
@@ -323,14 +359,14 @@ func resourcemimirRuleGroupAlertingDelete(ctx context.Context, d *schema.Resourc
 	return diag.Diagnostics{}
 }
 
-func ruleGroupAlertingRead(meta interface{}, name, namespace, orgID string) (string, error) {
+func ruleGroupAlertingRead(ctx context.Context, meta interface{}, name, namespace, orgID string) (string, error) {
 	headers := make(map[string]string)
 	if orgID != "" {
 		headers["X-Scope-OrgID"] = orgID
 	}
 	client := meta.(*apiClient)
 	path := rulesGroupPath(namespace, name)
-	jobraw, err := client.sendRequest("ruler", "GET", path, "", headers)
+	jobraw, err := client.sendRequest(ctx, "ruler", "GET", path, "", headers)
 	baseMsg := fmt.Sprintf("Cannot read alerting rule group '%s' (namespace: %s) -", name, namespace)
 	return jobraw, handleHTTPError(err, baseMsg)
 }
@@ -2,6 +2,7 @@ package mimir
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
@@ -18,6 +19,12 @@ func resourcemimirRuleGroupRecording() *schema.Resource {
 		ReadContext:   resourcemimirRuleGroupRecordingRead,
 		UpdateContext: resourcemimirRuleGroupRecordingUpdate,
 		DeleteContext: resourcemimirRuleGroupRecordingDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -106,12 +113,31 @@ func resourcemimirRuleGroupRecording() *schema.Resource {
 					},
 				},
 			},
+			"request_headers": requestHeadersSchema("resource"),
+			"auth":            requestAuthSchema("resource"),
 		}, /* End schema */
+		CustomizeDiff: func(ctx context.Context, diff *schema.ResourceDiff, v interface{}) error {
+			if diff.Get("query_offset").(string) != "" {
+				if diags := requireFeature(v, featureQueryOffset); diags.HasError() {
+					return errors.New(diags[0].Summary)
+				}
+			}
+
+			// Rules computed from other resources are only linted once known.
+			if !diff.NewValueKnown("rule") || !diff.NewValueKnown(labelsKey) {
+				return nil
+			}
+			ruleGroups := recordingRuleGroupLintInput(diff.Get)
+			if !ruleGroupsKnown(ruleGroups) {
+				return nil
+			}
+			return ruleLintCheck(ctx, diff.Get(namespaceKey).(string), ruleGroups)
+		},
 	}
 }
 
 func resourcemimirRuleGroupRecordingCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*apiClient)
+	client := meta.(*apiClient).forResource(d)
 	name := d.Get("name").(string)
 	namespace := d.Get(namespaceKey).(string)
 	orgID := d.Get(orgIDKey).(string)
@@ -124,7 +150,7 @@ func resourcemimirRuleGroupRecordingCreate(ctx context.Context, d *schema.Resour
 		if orgID != "" {
 			headers["X-Scope-OrgID"] = orgID
 		}
-		_, err := client.sendRequest("ruler", "GET", path, "", headers)
+		_, err := client.sendRequest(ctx, "ruler", "GET", path, "", headers)
 		baseMsg := fmt.Sprintf("Cannot create recording rule group '%s' (namespace: %s) -", name, namespace)
 		err = handleHTTPError(err, baseMsg)
 		if err != nil {
@@ -163,13 +189,15 @@ func resourcemimirRuleGroupRecordingCreate(ctx context.Context, d *schema.Resour
 	}
 
 	path := rulesNamespacePath(namespace)
-	_, err := client.sendRequest("ruler", "POST", path, string(data), headers)
+	_, err := client.sendRequest(ctx, "ruler", "POST", path, string(data), headers)
 	baseMsg := fmt.Sprintf("Cannot create recording rule group '%s' (namespace: %s) -", name, namespace)
 	err = handleHTTPError(err, baseMsg)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 	d.SetId(buildRuleGroupID(orgID, namespace, name))
+	lintDiags := ruleLintWarnings(namespace, recordingRuleGroupLintInput(d.Get))
+	lintDiags = append(lintDiags, ruleGroupFeatureWarnings(meta, recordingRuleGroupLintInput(d.Get))...)
 
 	// Retry read as mimir api could return a 404 status code caused by the event change notification propagation.
 	// Add delay of <ruleGroupReadDelayAfterChange> * time.Second) between each retry with a <ruleGroupReadRetryAfterChange> max retries.
@@ -177,12 +205,14 @@ func resourcemimirRuleGroupRecordingCreate(ctx context.Context, d *schema.Resour
 		result := resourcemimirRuleGroupRecordingRead(ctx, d, meta)
 		if len(result) > 0 && !result.HasError() {
 			log.Printf("[WARN] Recording rule group previously created'%s' not found (%d/3)", name, i)
-			time.Sleep(ruleGroupReadDelayAfterChangeDuration)
+			if err := sleepContext(ctx, ruleGroupReadDelayAfterChangeDuration); err != nil {
+				return diag.FromErr(err)
+			}
 			continue
 		}
-		return result
+		return append(lintDiags, result...)
 	}
-	return resourcemimirRuleGroupRecordingRead(ctx, d, meta)
+	return append(lintDiags, resourcemimirRuleGroupRecordingRead(ctx, d, meta)...)
 }
 
 func resourcemimirRuleGroupRecordingRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -195,7 +225,7 @@ func resourcemimirRuleGroupRecordingRead(ctx context.Context, d *schema.Resource
 		return diag.FromErr(err)
 	}
 
-	jobraw, err := ruleGroupRecordingRead(meta, name, namespace, orgID)
+	jobraw, err := ruleGroupRecordingRead(ctx, meta.(*apiClient).forResource(d), name, namespace, orgID)
 	if err != nil {
 		if d.IsNewResource() && strings.Contains(err.Error(), "response code '404'") {
 			diags = append(diags, diag.Diagnostic{
@@ -264,7 +294,7 @@ func resourcemimirRuleGroupRecordingRead(ctx context.Context, d *schema.Resource
 
 func resourcemimirRuleGroupRecordingUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	if d.HasChanges("rule", intervalKey, "query_offset", "evaluation_delay", "source_tenants", labelsKey) {
-		client := meta.(*apiClient)
+		client := meta.(*apiClient).forResource(d)
 		name := d.Get("name").(string)
 		namespace := d.Get(namespaceKey).(string)
 		orgID := d.Get(orgIDKey).(string)
@@ -290,7 +320,7 @@ func resourcemimirRuleGroupRecordingUpdate(ctx context.Context, d *schema.Resour
 		}
 
 		path := rulesNamespacePath(namespace)
-		_, err := client.sendRequest("ruler", "POST", path, string(data), headers)
+		_, err := client.sendRequest(ctx, "ruler", "POST", path, string(data), headers)
 		baseMsg := fmt.Sprintf("Cannot update recording rule group '%s' (namespace: %s)  -", name, namespace)
 		err = handleHTTPError(err, baseMsg)
 		if err != nil {
@@ -298,12 +328,16 @@ func resourcemimirRuleGroupRecordingUpdate(ctx context.Context, d *schema.Resour
 		}
 	}
 	// Add time delay before read to wait the event change notification propagation to finish
-	time.Sleep(ruleGroupReadDelayAfterChangeDuration)
-	return resourcemimirRuleGroupRecordingRead(ctx, d, meta)
+	if err := sleepContext(ctx, ruleGroupReadDelayAfterChangeDuration); err != nil {
+		return diag.FromErr(err)
+	}
+	lintDiags := ruleLintWarnings(d.Get(namespaceKey).(string), recordingRuleGroupLintInput(d.Get))
+	lintDiags = append(lintDiags, ruleGroupFeatureWarnings(meta, recordingRuleGroupLintInput(d.Get))...)
+	return append(lintDiags, resourcemimirRuleGroupRecordingRead(ctx, d, meta)...)
 }
 
 func resourcemimirRuleGroupRecordingDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*apiClient)
+	client := meta.(*apiClient).forResource(d)
 	name := d.Get("name").(string)
 	namespace := d.Get(namespaceKey).(string)
 	orgID := d.Get(orgIDKey).(string)
@@ -313,7 +347,7 @@ func resourcemimirRuleGroupRecordingDelete(ctx context.Context, d *schema.Resour
 		headers["X-Scope-OrgID"] = orgID
 	}
 	path := rulesGroupPath(namespace, name)
-	_, err := client.sendRequest("ruler", "DELETE", path, "", headers)
+	_, err := client.sendRequest(ctx, "ruler", "DELETE", path, "", headers)
 	if err != nil {
 		return diag.FromErr(fmt.Errorf(
 			"cannot delete recording rule group '%s' from %s: %v",
@@ -324,10 +358,12 @@ func resourcemimirRuleGroupRecordingDelete(ctx context.Context, d *schema.Resour
 	// Retry read as mimir api could return a 200 status code but the rule group still exist because of the event change notification propagation latency.
 	// Add delay of <ruleGroupReadDelayAfterChange> * time.Second) between each retry with a <ruleGroupReadRetryAfterChange> max retries.
 	for i := 1; i <= ruleGroupReadRetryAfterChange; i++ {
-		_, err := ruleGroupRecordingRead(meta, name, namespace, orgID)
+		_, err := ruleGroupRecordingRead(ctx, client, name, namespace, orgID)
 		if err == nil {
 			log.Printf("[WARN] Recording rule group previously deleted '%s' still exist (%d/3)", name, i)
-			time.Sleep(ruleGroupReadDelayAfterChangeDuration)
+			if err := sleepContext(ctx, ruleGroupReadDelayAfterChangeDuration); err != nil {
+				return diag.FromErr(err)
+			}
 			continue
 		} else if strings.Contains(err.Error(), "response code '404'") {
 			break
@@ -338,14 +374,14 @@ func resourcemimirRuleGroupRecordingDelete(ctx context.Context, d *schema.Resour
 	return diag.Diagnostics{}
 }
 
-func ruleGroupRecordingRead(meta interface{}, name, namespace, orgID string) (string, error) {
+func ruleGroupRecordingRead(ctx context.Context, meta interface{}, name, namespace, orgID string) (string, error) {
 	headers := make(map[string]string)
 	if orgID != "" {
 		headers["X-Scope-OrgID"] = orgID
 	}
 	client := meta.(*apiClient)
 	path := rulesGroupPath(namespace, name)
-	jobraw, err := client.sendRequest("ruler", "GET", path, "", headers)
+	jobraw, err := client.sendRequest(ctx, "ruler", "GET", path, "", headers)
 	baseMsg := fmt.Sprintf("Cannot read recording rule group '%s' (namespace: %s) -", name, namespace)
 	return jobraw, handleHTTPError(err, baseMsg)
 }
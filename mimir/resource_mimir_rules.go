@@ -3,11 +3,15 @@ package mimir
 import (
 	"context"
 	"crypto/sha256"
+	"errors"
 	"fmt"
-	"os"
+	"net/url"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -31,6 +35,9 @@ type RuleGroup struct {
 	// Labels are group-level labels applied to all rules in the group.
 	// Requires Mimir >= 3.0.0 to be persisted (older Mimir accepts but drops them).
 	Labels map[string]string `yaml:"labels,omitempty"`
+
+	// file is the content file the group was read from, never sent to Mimir.
+	file string
 }
 
 // Rule represents both alerting and recording rules
@@ -55,13 +62,23 @@ func resourceMimirRules() *schema.Resource {
 		This resource is designed to handle YAML files containing multiple rule groups, 
 		such as those exported from mimirtool or monitoring mixins. Each rule group 
 		is managed individually via the Mimir API, but they are tracked together as 
-		a single Terraform resource for easier bulk management.`,
+		a single Terraform resource for easier bulk management. When some groups 
+		fail to be written, the others are recorded in the state and the next apply 
+		only writes the groups left (after a failed create, untaint the resource 
+		to resume instead of recreating it).`,
 
 		CreateContext: resourceMimirRulesCreate,
 		ReadContext:   resourceMimirRulesRead,
 		UpdateContext: resourceMimirRulesUpdate,
 		DeleteContext: resourceMimirRulesDelete,
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
 		Importer: &schema.ResourceImporter{
 			StateContext: resourceMimirRulesImport,
 		},
@@ -85,19 +102,51 @@ func resourceMimirRules() *schema.Resource {
 
 			// Content input methods (mutually exclusive)
 			"content": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "YAML content containing rule groups. Mutually exclusive with 'content_file', 'content_files' and 'content_dir'. Group-level `labels` are supported and require Mimir >= 3.0.0 to be persisted (older Mimir accepts but drops them).",
+				ValidateFunc:     validateYAMLContent,
+				DiffSuppressFunc: suppressEquivalentRuleGroupsContent,
+				ConflictsWith:    []string{"content_file", "content_files", "content_dir"},
+			},
+
+			"content_file": {
 				Type:          schema.TypeString,
 				Optional:      true,
-				Description:   "YAML content containing rule groups. Mutually exclusive with 'content_file'. Group-level `labels` are supported and require Mimir >= 3.0.0 to be persisted (older Mimir accepts but drops them).",
-				ValidateFunc:  validateYAMLContent,
-				ConflictsWith: []string{"content_file"},
+				Description:   "Path to YAML file containing rule groups. Mutually exclusive with 'content', 'content_files' and 'content_dir'. Group-level `labels` in the file are supported and require Mimir >= 3.0.0 to be persisted (older Mimir accepts but drops them).",
+				ValidateFunc:  validation.StringIsNotEmpty,
+				ConflictsWith: []string{"content", "content_files", "content_dir"},
 			},
 
-			"content_file": {
+			"content_files": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				Description:   "Paths or glob patterns (e.g. `rules/*.yaml`) of YAML files containing rule groups, merged into the namespace. A group name may only be defined once across the files. Mutually exclusive with 'content', 'content_file' and 'content_dir'.",
+				Elem:          &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.StringIsNotEmpty},
+				ConflictsWith: []string{"content", "content_file", "content_dir"},
+			},
+
+			"content_format": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      ruleContentFormatRules,
+				Description:  "Format of the content: `rules` for rule groups files (`groups: [...]`), or `prometheusrule` for Prometheus Operator `PrometheusRule` manifests, whose `spec.groups` are read. Both accept multi-document YAML.",
+				ValidateFunc: validation.StringInSlice([]string{ruleContentFormatRules, ruleContentFormatPrometheusRule}, false),
+			},
+
+			"namespace_from": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "With `content_format = \"prometheusrule\"`, the manifest field mapped to the Mimir namespace, `metadata.namespace` or `metadata.name`: only the groups of the manifests whose field equals `namespace` are managed, so one set of manifests can feed a resource per namespace.",
+				ValidateFunc: validation.StringInSlice([]string{"metadata.namespace", "metadata.name"}, false),
+			},
+
+			"content_dir": {
 				Type:          schema.TypeString,
 				Optional:      true,
-				Description:   "Path to YAML file containing rule groups. Mutually exclusive with 'content'. Group-level `labels` in the file are supported and require Mimir >= 3.0.0 to be persisted (older Mimir accepts but drops them).",
+				Description:   "Directory whose `.yaml` and `.yml` files contain rule groups, merged into the namespace in file name order. A group name may only be defined once across the files. Mutually exclusive with 'content', 'content_file' and 'content_files'.",
 				ValidateFunc:  validation.StringIsNotEmpty,
-				ConflictsWith: []string{"content"},
+				ConflictsWith: []string{"content", "content_file", "content_files"},
 			},
 
 			// Management options
@@ -116,6 +165,13 @@ func resourceMimirRules() *schema.Resource {
 				ConflictsWith: []string{"only_groups"},
 			},
 
+			"parallelism": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Number of rule groups read, created, updated or deleted concurrently. Defaults to the provider `parallelism`.",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+
 			// Read-only computed fields
 			"managed_groups": {
 				Type:        schema.TypeList,
@@ -181,9 +237,21 @@ func resourceMimirRules() *schema.Resource {
 							Computed:    true,
 							Description: "Number of recording rules in this group",
 						},
+						"file": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "File the rule group is read from, empty for 'content'",
+						},
+						"hash": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Hash of the rule group as last written to Mimir. Groups whose hash differs from the content are written on the next apply.",
+						},
 					},
 				},
 			},
+			"request_headers": requestHeadersSchema("resource"),
+			"auth":            requestAuthSchema("resource"),
 		},
 
 		CustomizeDiff: func(ctx context.Context, diff *schema.ResourceDiff, v interface{}) error {
@@ -191,20 +259,13 @@ func resourceMimirRules() *schema.Resource {
 				return err
 			}
 
-			// Parse the configuration to determine what will be managed
-			var ruleGroups RuleGroups
-			var err error
-			var fileContent []byte
-
-			if content := diff.Get("content").(string); content != "" {
-				err = yaml.Unmarshal([]byte(content), &ruleGroups)
-			} else if contentFile := diff.Get("content_file").(string); contentFile != "" {
-				fileContent, err = os.ReadFile(contentFile)
-				if err == nil {
-					err = yaml.Unmarshal(fileContent, &ruleGroups)
-				}
+			// Parse the configuration to determine what will be managed. The files may
+			// not exist yet at plan time, but a group defined in two files never works.
+			ruleGroups, err := readRuleGroupsSources(diff.Get)
+			var duplicate *duplicateRuleGroupError
+			if errors.As(err, &duplicate) {
+				return err
 			}
-
 			if err != nil || len(ruleGroups.Groups) == 0 {
 				return nil
 			}
@@ -237,19 +298,65 @@ func resourceMimirRules() *schema.Resource {
 				managedGroups = allGroupNames
 			}
 
+			if err := ruleLintCheck(ctx, diff.Get(namespaceKey).(string), filterManagedGroups(ruleGroups, managedGroups)); err != nil {
+				return err
+			}
+
 			// Calculate new content hash
 			newContentHash := calculateContentHash(ruleGroups, managedGroups)
 			oldContentHash := diff.Get("content_hash").(string)
 
 			// Check if content has changed (either directly or via file modification)
-			contentChanged := diff.HasChange("content") || diff.HasChange("only_groups") || diff.HasChange("ignore_groups") || diff.Id() == ""
-			if !contentChanged && diff.Get("content_file").(string) != "" {
-				// For content_file, compare hashes to detect file content changes
+			// HasChange does not apply the DiffSuppressFunc of content
+			oldContent, newContent := diff.GetChange("content")
+			contentChanged := diff.HasChange("content") && !equivalentRuleGroupsContent(oldContent.(string), newContent.(string), diff.Get) ||
+				diff.HasChange("only_groups") || diff.HasChange("ignore_groups") ||
+				diff.HasChange("content_format") || diff.HasChange("namespace_from") || diff.Id() == ""
+			if !contentChanged && diff.Get("content").(string) == "" {
+				// For content files, compare hashes to detect file content changes
 				contentChanged = newContentHash != oldContentHash
 			}
+			if !contentChanged && diff.Id() != "" {
+				// Resume the groups a previous apply failed to write or delete
+				var stateManaged []string
+				for _, name := range diff.Get("managed_groups").([]interface{}) {
+					stateManaged = append(stateManaged, name.(string))
+				}
+				desired := ruleGroupHashes(filterManagedGroups(ruleGroups, managedGroups))
+				if pending := pendingRuleGroups(desired, appliedRuleGroupHashes(diff.Get("groups")), stateManaged); len(pending) > 0 {
+					tflog.Info(ctx, "Rule groups not fully applied, they will be written again", map[string]interface{}{
+						"namespace":   diff.Get(namespaceKey).(string),
+						"rule_groups": pending,
+					})
+					contentChanged = true
+				}
+			}
 
 			if contentChanged {
+				// Fail the plan rather than the apply, halfway, on the ruler limits
+				if client, ok := v.(*apiClient); ok && client != nil {
+					client = client.forResource(diff)
+					orgID := diff.Get(orgIDKey).(string)
+					if orgID == "" {
+						orgID = client.headers["X-Scope-OrgID"]
+					}
+					var previouslyManaged []string
+					if diff.Id() != "" {
+						old, _ := diff.GetChange("managed_groups")
+						for _, name := range old.([]interface{}) {
+							previouslyManaged = append(previouslyManaged, name.(string))
+						}
+					}
+					namespace := diff.Get(namespaceKey).(string)
+					if findings := tenantLimitsFindings(ctx, client, orgID, namespace, filterManagedGroups(ruleGroups, managedGroups), previouslyManaged); len(findings) > 0 {
+						return fmt.Errorf("tenant limits exceeded (org_id: %s, namespace: %s):\n  - %s", orgID, namespace, strings.Join(findings, "\n  - "))
+					}
+				}
+
 				// Set the computed fields so they appear in the plan
+				if err := diff.SetNewComputed("groups"); err != nil {
+					return err
+				}
 				diff.SetNew("managed_groups", managedGroups)
 				diff.SetNew("groups_count", len(managedGroups))
 				diff.SetNew("content_hash", newContentHash)
@@ -287,8 +394,25 @@ func validateYAMLContent(val interface{}, key string) (warns []string, errs []er
 		return
 	}
 
-	var ruleGroups RuleGroups
-	if err := yaml.Unmarshal([]byte(content), &ruleGroups); err != nil {
+	// The content format is not known here: PrometheusRule manifests are recognised by
+	// their kind, and the groups of each manifest are validated on their own since
+	// namespace_from may send them to different namespaces.
+	if docs, err := yamlDocuments([]byte(content)); err == nil && len(docs) > 0 && documentKind(docs[0]) == "PrometheusRule" {
+		rules, err := parsePrometheusRules([]byte(content))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%q contains invalid PrometheusRule manifests: %v", key, err))
+			return
+		}
+		for _, rule := range rules {
+			if err := validateRuleGroupsContent(RuleGroups{Groups: rule.Spec.Groups}); err != nil {
+				errs = append(errs, fmt.Errorf("%q validation failed for PrometheusRule '%s': %v", key, rule.Metadata.Name, err))
+			}
+		}
+		return
+	}
+
+	ruleGroups, err := parseRuleGroupsContent([]byte(content), ruleContentOptions{format: ruleContentFormatRules})
+	if err != nil {
 		errs = append(errs, fmt.Errorf("%q contains invalid YAML: %v", key, err))
 		return
 	}
@@ -338,6 +462,9 @@ func validateRuleGroupsContent(ruleGroups RuleGroups) error {
 			if err := validateRule(rule, i, j, group.Name); err != nil {
 				return err
 			}
+			if err := validateRuleTemplates(rule); err != nil {
+				return fmt.Errorf("group %d (%s), rule %d: %v", i, group.Name, j, err)
+			}
 		}
 	}
 
@@ -397,15 +524,30 @@ func validateRule(rule Rule, groupIndex, ruleIndex int, groupName string) error
 
 func validateRuleGroupsConfiguration(diff *schema.ResourceDiff) error {
 	// Ensure exactly one input method is used
-	hasContent := diff.Get("content").(string) != ""
-	hasContentFile := diff.Get("content_file").(string) != ""
+	var set []string
+	for _, key := range ruleContentSources {
+		switch v := diff.Get(key).(type) {
+		case string:
+			if v != "" {
+				set = append(set, key)
+			}
+		case []interface{}:
+			if len(v) > 0 {
+				set = append(set, key)
+			}
+		}
+	}
 
-	if !hasContent && !hasContentFile {
-		return fmt.Errorf("either 'content' or 'content_file' must be specified")
+	if len(set) == 0 {
+		return fmt.Errorf("one of 'content', 'content_file', 'content_files' or 'content_dir' must be specified")
 	}
 
-	if hasContent && hasContentFile {
-		return fmt.Errorf("'content' and 'content_file' are mutually exclusive")
+	if len(set) > 1 {
+		return fmt.Errorf("'%s' are mutually exclusive", strings.Join(set, "', '"))
+	}
+
+	if diff.Get("namespace_from").(string) != "" && diff.Get("content_format").(string) != ruleContentFormatPrometheusRule {
+		return fmt.Errorf("'namespace_from' requires content_format = %q", ruleContentFormatPrometheusRule)
 	}
 
 	return nil
@@ -414,7 +556,7 @@ func validateRuleGroupsConfiguration(diff *schema.ResourceDiff) error {
 // Resource CRUD operations
 
 func resourceMimirRulesCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*apiClient)
+	client := m.(*apiClient).forResource(d)
 
 	ruleGroups, err := parseRuleGroupsConfiguration(d)
 	if err != nil {
@@ -428,7 +570,7 @@ func resourceMimirRulesCreate(ctx context.Context, d *schema.ResourceData, m int
 	}
 
 	// Determine which groups to manage
-	managedGroups := determineGroupsToManage(ruleGroups, d)
+	managedGroups := determineGroupsToManage(ruleGroups, d.Get)
 	if len(managedGroups) == 0 {
 		return diag.FromErr(fmt.Errorf("no rule groups selected for management"))
 	}
@@ -439,35 +581,39 @@ func resourceMimirRulesCreate(ctx context.Context, d *schema.ResourceData, m int
 	resourceID := fmt.Sprintf("%s/%s", orgID, namespace)
 	d.SetId(resourceID)
 
-	// Create rule groups via API
-	var createdGroups []string
-	for _, group := range ruleGroups.Groups {
-		if !contains(managedGroups, group.Name) {
-			continue // Skip groups not selected for management
-		}
-
-		if err := createRuleGroup(client, namespace, orgID, group); err != nil {
-			// Record which groups were actually created so Delete can clean them
-			// up when Terraform destroys the tainted resource on the next apply.
-			d.Set("managed_groups", createdGroups)
-			return diag.FromErr(fmt.Errorf("failed to create rule group '%s': %w", group.Name, err))
-		}
-		createdGroups = append(createdGroups, group.Name)
+	// Create rule groups via API, <parallelism> at a time
+	names, groups := ruleGroupsByName(filterManagedGroups(ruleGroups, managedGroups))
+	errs := forEachRuleGroup(ctx, resourceRulesParallelism(d, m), names, func(ctx context.Context, name string) error {
+		return createRuleGroup(ctx, client, namespace, orgID, groups[name])
+	})
+	created := succeededRuleGroups(names, errs)
+	applied := make(map[string]string, len(created))
+	for _, name := range created {
+		applied[name] = ruleGroupHash(groups[name])
+	}
+	if len(errs) > 0 {
+		// Record which groups were actually created, with their hash, so Delete can
+		// clean them up when Terraform destroys the tainted resource on the next
+		// apply, or the next apply only writes the others once it is untainted.
+		setComputedFields(d, ruleGroups, created, applied)
+		return ruleGroupErrorDiags("create", names, errs)
 	}
 
 	// Add time delay before read to wait for event propagation
-	if ruleGroupReadDelayAfterChangeDuration > 0 {
-		time.Sleep(ruleGroupReadDelayAfterChangeDuration)
+	if err := sleepContext(ctx, ruleGroupReadDelayAfterChangeDuration); err != nil {
+		return diag.FromErr(err)
 	}
 
 	// Set computed fields
-	setComputedFields(d, ruleGroups, managedGroups)
+	setComputedFields(d, ruleGroups, managedGroups, applied)
 
-	return resourceMimirRulesRead(ctx, d, m)
+	lintDiags := ruleLintWarnings(namespace, filterManagedGroups(ruleGroups, managedGroups))
+	lintDiags = append(lintDiags, ruleGroupFeatureWarnings(m, filterManagedGroups(ruleGroups, managedGroups))...)
+	return append(lintDiags, resourceMimirRulesRead(ctx, d, m)...)
 }
 
 func resourceMimirRulesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*apiClient)
+	client := m.(*apiClient).forResource(d)
 
 	namespace := d.Get(namespaceKey).(string)
 	orgID := d.Get(orgIDKey).(string)
@@ -481,7 +627,14 @@ func resourceMimirRulesRead(ctx context.Context, d *schema.ResourceData, m inter
 		return diag.FromErr(err)
 	}
 
-	managedGroups := determineGroupsToManage(ruleGroups, d)
+	// Also check the groups still tracked from a partially failed apply, e.g. a group
+	// removed from the content which could not be deleted.
+	managedGroups := determineGroupsToManage(ruleGroups, d.Get)
+	for _, name := range d.Get("managed_groups").([]interface{}) {
+		if !contains(managedGroups, name.(string)) {
+			managedGroups = append(managedGroups, name.(string))
+		}
+	}
 
 	// Verify that all managed groups still exist
 	headers := make(map[string]string)
@@ -489,19 +642,24 @@ func resourceMimirRulesRead(ctx context.Context, d *schema.ResourceData, m inter
 		headers["X-Scope-OrgID"] = orgID
 	}
 
-	var existingGroups []string
-	for _, groupName := range managedGroups {
+	var deletedMu sync.Mutex
+	deleted := make(map[string]error)
+	errs := forEachRuleGroup(ctx, resourceRulesParallelism(d, m), managedGroups, func(ctx context.Context, groupName string) error {
 		path := rulesGroupPath(namespace, groupName)
-		_, err := client.sendRequest("ruler", "GET", path, "", headers)
-		if err != nil {
-			if strings.Contains(err.Error(), "does not exist") {
-				// Group was deleted outside of Terraform
-				continue
-			}
-			return diag.FromErr(fmt.Errorf("failed to read rule group '%s': %w", groupName, err))
+		_, err := client.sendRequest(ctx, "ruler", "GET", path, "", headers)
+		if err != nil && strings.Contains(err.Error(), "does not exist") {
+			// Group was deleted outside of Terraform
+			deletedMu.Lock()
+			deleted[groupName] = err
+			deletedMu.Unlock()
+			return nil
 		}
-		existingGroups = append(existingGroups, groupName)
+		return err
+	})
+	if len(errs) > 0 {
+		return ruleGroupErrorDiags("read", managedGroups, errs)
 	}
+	existingGroups := succeededRuleGroups(managedGroups, deleted)
 
 	// If no groups exist, mark resource as deleted
 	if len(existingGroups) == 0 {
@@ -509,14 +667,15 @@ func resourceMimirRulesRead(ctx context.Context, d *schema.ResourceData, m inter
 		return nil
 	}
 
-	// Update computed fields based on what actually exists
-	setComputedFields(d, ruleGroups, existingGroups)
+	// Update computed fields based on what actually exists, keeping the hash of the
+	// groups as last written
+	setComputedFields(d, ruleGroups, existingGroups, appliedRuleGroupHashes(d.Get("groups")))
 
 	return nil
 }
 
 func resourceMimirRulesUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*apiClient)
+	client := m.(*apiClient).forResource(d)
 
 	namespace := d.Get(namespaceKey).(string)
 	orgID := d.Get(orgIDKey).(string)
@@ -538,7 +697,7 @@ func resourceMimirRulesUpdate(ctx context.Context, d *schema.ResourceData, m int
 	// (they become orphaned, "unmanaged" rule groups).
 	oldManagedGroupsRaw, _ := d.GetChange("managed_groups")
 	oldManagedGroups := oldManagedGroupsRaw.([]interface{})
-	newManagedGroups := determineGroupsToManage(newRuleGroups, d)
+	newManagedGroups := determineGroupsToManage(newRuleGroups, d.Get)
 
 	// Convert old managed groups to string slice
 	var oldGroups []string
@@ -546,50 +705,74 @@ func resourceMimirRulesUpdate(ctx context.Context, d *schema.ResourceData, m int
 		oldGroups = append(oldGroups, g.(string))
 	}
 
-	// Determine what needs to be done
+	// Determine what needs to be done: only the groups whose content differs from the
+	// one last written are pushed, which also resumes a partially failed apply.
+	oldGroupDetails, _ := d.GetChange("groups")
+	applied := appliedRuleGroupHashes(oldGroupDetails)
 	groupsToDelete := difference(oldGroups, newManagedGroups)
-	groupsToCreateOrUpdate := newManagedGroups
-
-	// Delete removed groups
-	for _, groupName := range groupsToDelete {
-		if err := deleteRuleGroup(client, namespace, orgID, groupName); err != nil {
-			return diag.FromErr(fmt.Errorf("failed to delete rule group '%s': %w", groupName, err))
+	var groupsToCreateOrUpdate []string
+	for name, hash := range ruleGroupHashes(filterManagedGroups(newRuleGroups, newManagedGroups)) {
+		if applied[name] != hash {
+			groupsToCreateOrUpdate = append(groupsToCreateOrUpdate, name)
 		}
 	}
 
-	// Create or update groups
-	for _, group := range newRuleGroups.Groups {
-		if !contains(groupsToCreateOrUpdate, group.Name) {
-			continue
-		}
+	// Delete removed groups, then create or update the others, <parallelism> at a time
+	parallelism := resourceRulesParallelism(d, m)
+	deleteErrs := forEachRuleGroup(ctx, parallelism, groupsToDelete, func(ctx context.Context, name string) error {
+		return deleteRuleGroup(ctx, client, namespace, orgID, name)
+	})
+	names, groups := ruleGroupsByName(filterManagedGroups(newRuleGroups, groupsToCreateOrUpdate))
+	writeErrs := forEachRuleGroup(ctx, parallelism, names, func(ctx context.Context, name string) error {
+		return createRuleGroup(ctx, client, namespace, orgID, groups[name])
+	})
+	deleted := succeededRuleGroups(groupsToDelete, deleteErrs)
+	for _, name := range deleted {
+		delete(applied, name)
+	}
+	for _, name := range succeededRuleGroups(names, writeErrs) {
+		applied[name] = ruleGroupHash(groups[name])
+	}
 
-		if err := createRuleGroup(client, namespace, orgID, group); err != nil {
-			// Restore the old computed fields so that the state reflects the
-			// actual Mimir state. Without this, Terraform SDK v2 may persist the
-			// planned new values (e.g. content_hash) into the state even though
-			// the update was rejected, causing a subsequent plan to appear clean
-			// despite the change never being applied.
-			oldHash, _ := d.GetChange("content_hash")
-			d.Set("content_hash", oldHash)
-			oldManagedGroupsRaw, _ := d.GetChange("managed_groups")
-			d.Set("managed_groups", oldManagedGroupsRaw)
-			return diag.FromErr(fmt.Errorf("failed to create/update rule group '%s': %w", group.Name, err))
+	if len(deleteErrs) > 0 || len(writeErrs) > 0 {
+		// Keep track of every group now in Mimir, with the hash of its content:
+		// the ones written, plus the old ones that failed to be deleted or updated.
+		var managed []string
+		for _, name := range append(append([]string{}, newManagedGroups...), oldGroups...) {
+			_, written := applied[name]
+			if (written || contains(oldGroups, name)) && !contains(deleted, name) && !contains(managed, name) {
+				managed = append(managed, name)
+			}
 		}
+		setComputedFields(d, newRuleGroups, managed, applied)
+
+		// Restore the old content_hash so that the state reflects the
+		// actual Mimir state. Without this, Terraform SDK v2 may persist the
+		// planned new values into the state even though the update was
+		// rejected, causing a subsequent plan to appear clean despite the
+		// change never being applied.
+		oldHash, _ := d.GetChange("content_hash")
+		d.Set("content_hash", oldHash)
+
+		diags := ruleGroupErrorDiags("delete", groupsToDelete, deleteErrs)
+		return append(diags, ruleGroupErrorDiags("create/update", names, writeErrs)...)
 	}
 
 	// Add time delay before read to wait for event propagation
-	if ruleGroupReadDelayAfterChangeDuration > 0 {
-		time.Sleep(ruleGroupReadDelayAfterChangeDuration)
+	if err := sleepContext(ctx, ruleGroupReadDelayAfterChangeDuration); err != nil {
+		return diag.FromErr(err)
 	}
 
 	// Update computed fields
-	setComputedFields(d, newRuleGroups, newManagedGroups)
+	setComputedFields(d, newRuleGroups, newManagedGroups, applied)
 
-	return resourceMimirRulesRead(ctx, d, m)
+	lintDiags := ruleLintWarnings(namespace, filterManagedGroups(newRuleGroups, newManagedGroups))
+	lintDiags = append(lintDiags, ruleGroupFeatureWarnings(m, filterManagedGroups(newRuleGroups, newManagedGroups))...)
+	return append(lintDiags, resourceMimirRulesRead(ctx, d, m)...)
 }
 
 func resourceMimirRulesDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*apiClient)
+	client := m.(*apiClient).forResource(d)
 
 	namespace := d.Get(namespaceKey).(string)
 	orgID := d.Get(orgIDKey).(string)
@@ -604,16 +787,20 @@ func resourceMimirRulesDelete(ctx context.Context, d *schema.ResourceData, m int
 		managedGroups = append(managedGroups, g.(string))
 	}
 
-	// Delete each managed rule group
-	var errors []string
-	for _, groupName := range managedGroups {
-		if err := deleteRuleGroup(client, namespace, orgID, groupName); err != nil {
-			errors = append(errors, fmt.Sprintf("failed to delete rule group '%s': %v", groupName, err))
+	// Delete each managed rule group, <parallelism> at a time
+	errs := forEachRuleGroup(ctx, resourceRulesParallelism(d, m), managedGroups, func(ctx context.Context, name string) error {
+		return deleteRuleGroup(ctx, client, namespace, orgID, name)
+	})
+	if len(errs) > 0 {
+		// Only the groups left in Mimir are deleted again on the next destroy
+		var remaining []string
+		for _, name := range managedGroups {
+			if _, failed := errs[name]; failed {
+				remaining = append(remaining, name)
+			}
 		}
-	}
-
-	if len(errors) > 0 {
-		return diag.FromErr(fmt.Errorf("errors during deletion: %s", strings.Join(errors, "; ")))
+		d.Set("managed_groups", remaining)
+		return ruleGroupErrorDiags("delete", managedGroups, errs)
 	}
 
 	d.SetId("")
@@ -621,66 +808,149 @@ func resourceMimirRulesDelete(ctx context.Context, d *schema.ResourceData, m int
 }
 
 func resourceMimirRulesImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-	// Import format: orgID/namespace
-	parts := strings.Split(d.Id(), "/")
-	if len(parts) != 2 {
-		return nil, fmt.Errorf("import ID must be in format: orgID/namespace")
+	client := m.(*apiClient).forResource(d)
+
+	// Import format: orgID/namespace[?groups=a,b]
+	orgID, namespace, onlyGroups, err := parseRulesImportID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	headers := make(map[string]string)
+	if orgID != "" {
+		headers["X-Scope-OrgID"] = orgID
+	}
+	resp, err := client.sendRequest(ctx, "ruler", "GET", rulesNamespacePath(namespace), "", headers)
+	err = handleHTTPError(err, fmt.Sprintf("Cannot read rule groups (namespace: %s) -", namespace))
+	if err != nil {
+		return nil, err
 	}
 
-	orgID := parts[0]
-	namespace := parts[1]
+	// The ruler answers with a namespace-keyed map holding this single namespace.
+	var byNamespace map[string][]RuleGroup
+	if err := yaml.Unmarshal([]byte(resp), &byNamespace); err != nil {
+		return nil, fmt.Errorf("unable to decode rule groups of namespace '%s': %v", namespace, err)
+	}
+	ruleGroups := RuleGroups{Groups: byNamespace[namespace]}
+	if len(ruleGroups.Groups) == 0 {
+		return nil, fmt.Errorf("namespace '%s' has no rule groups to import", namespace)
+	}
 
-	// ValidateFunc does not run on values an importer sets via d.Set, so re-validate here:
-	// reject control chars / '/' / dot-segments before they reach the ruler path or the
-	// X-Scope-OrgID header. (The strict 2-part split already blocks a '/'-fabrication.)
-	if !validOrgID(orgID) {
-		return nil, fmt.Errorf("import ID %q: org_id must contain no control characters", d.Id())
+	var names []string
+	for _, group := range ruleGroups.Groups {
+		names = append(names, group.Name)
 	}
-	if !validNamespace(namespace) {
-		return nil, fmt.Errorf("import ID %q: namespace %q must not be \".\"/\"..\" and must contain no control characters or '/'", d.Id(), namespace)
+	for _, name := range onlyGroups {
+		if !contains(names, name) {
+			return nil, fmt.Errorf("rule group '%s' not found in namespace '%s' (available: %s)", name, namespace, strings.Join(names, ", "))
+		}
+	}
+
+	content, err := normalizeRuleGroupsContent(ruleGroups)
+	if err != nil {
+		return nil, err
 	}
 
+	d.SetId(fmt.Sprintf("%s/%s", orgID, namespace))
 	d.Set(orgIDKey, orgID)
 	d.Set(namespaceKey, namespace)
-
-	// Note: For import, the user will need to provide content/content_file afterward
-	// We can't automatically detect the YAML content from the API
+	d.Set("content", content)
+	d.Set("content_format", ruleContentFormatRules)
+	d.Set("only_groups", onlyGroups)
 
 	return []*schema.ResourceData{d}, nil
 }
 
-// Helper functions
+// buildRulesImportID encodes a mimir_rules import ID, the reverse of parseRulesImportID.
+func buildRulesImportID(orgID, namespace string, groups []string) string {
+	id := url.PathEscape(orgID) + "/" + url.PathEscape(namespace)
+	if len(groups) == 0 {
+		return id
+	}
+	escaped := make([]string, 0, len(groups))
+	for _, name := range groups {
+		escaped = append(escaped, url.QueryEscape(name))
+	}
+	return id + "?groups=" + strings.Join(escaped, ",")
+}
 
-func parseRuleGroupsConfiguration(d *schema.ResourceData) (RuleGroups, error) {
-	var ruleGroups RuleGroups
+// parseRulesImportID splits a mimir_rules import ID of the form "orgID/namespace", optionally
+// followed by "?groups=a,b" to only manage some groups of the namespace. Like
+// parseRuleGroupID, each segment may be URL-escaped and is re-validated after unescaping,
+// since ValidateFunc does not run on values an importer sets via d.Set. Group names are
+// split on raw commas before unescaping, so a literal ',' in a name is written %2C.
+func parseRulesImportID(id string) (orgID, namespace string, groups []string, err error) {
+	path, query, _ := strings.Cut(id, "?")
 
-	if content := d.Get("content").(string); content != "" {
-		if err := yaml.Unmarshal([]byte(content), &ruleGroups); err != nil {
-			return ruleGroups, fmt.Errorf("failed to parse YAML content: %w", err)
+	parts := strings.Split(path, "/")
+	if len(parts) != 2 {
+		return "", "", nil, fmt.Errorf("import ID must be in format: orgID/namespace or orgID/namespace?groups=group1,group2")
+	}
+	var dec [2]string
+	for i, seg := range parts {
+		v, uerr := url.PathUnescape(seg)
+		if uerr != nil {
+			// Same legacy fallback as parseRuleGroupID: a bare '%' is kept as-is.
+			v = seg
 		}
-	} else if contentFile := d.Get("content_file").(string); contentFile != "" {
-		data, err := os.ReadFile(contentFile)
-		if err != nil {
-			return ruleGroups, fmt.Errorf("failed to read file %s: %w", contentFile, err)
+		dec[i] = v
+	}
+	orgID, namespace = dec[0], dec[1]
+
+	// Reject control chars / '/' / dot-segments before they reach the ruler path or the
+	// X-Scope-OrgID header. (The strict 2-part split already blocks a '/'-fabrication.)
+	if !validOrgID(orgID) {
+		return "", "", nil, fmt.Errorf("import ID %q: org_id must contain no control characters", id)
+	}
+	if !validNamespace(namespace) || namespace == "" {
+		return "", "", nil, fmt.Errorf("import ID %q: namespace %q must be non-empty, not \".\"/\"..\" and must contain no control characters or '/'", id, namespace)
+	}
+
+	if query == "" {
+		return orgID, namespace, nil, nil
+	}
+	for _, param := range strings.Split(query, "&") {
+		key, value, _ := strings.Cut(param, "=")
+		if key != "groups" {
+			return "", "", nil, fmt.Errorf("import ID %q: unknown parameter %q, only 'groups' is supported", id, key)
 		}
-		if err := yaml.Unmarshal(data, &ruleGroups); err != nil {
-			return ruleGroups, fmt.Errorf("failed to parse YAML file %s: %w", contentFile, err)
+		for _, raw := range strings.Split(value, ",") {
+			name, uerr := url.QueryUnescape(raw)
+			if uerr != nil {
+				return "", "", nil, fmt.Errorf("import ID %q: invalid group name encoding %q: %v", id, raw, uerr)
+			}
+			if !validRuleName(name) {
+				return "", "", nil, fmt.Errorf("import ID %q: group name %q must be non-empty, not whitespace-only, not \".\"/\"..\", and contain no control characters or '/'", id, name)
+			}
+			if !contains(groups, name) {
+				groups = append(groups, name)
+			}
 		}
-	} else {
-		return ruleGroups, fmt.Errorf("no rule configuration provided")
+	}
+	return orgID, namespace, groups, nil
+}
+
+// Helper functions
+
+func parseRuleGroupsConfiguration(d *schema.ResourceData) (RuleGroups, error) {
+	ruleGroups, err := readRuleGroupsSources(d.Get)
+	if err != nil {
+		return ruleGroups, err
 	}
 
 	return ruleGroups, validateRuleGroupsContent(ruleGroups)
 }
 
-func determineGroupsToManage(ruleGroups RuleGroups, d *schema.ResourceData) []string {
+// determineGroupsToManage returns the names of the groups selected by only_groups or
+// ignore_groups, read through get (d.Get or diff.Get).
+func determineGroupsToManage(ruleGroups RuleGroups, get func(string) interface{}) []string {
 	allGroupNames := make([]string, len(ruleGroups.Groups))
 	for i, group := range ruleGroups.Groups {
 		allGroupNames[i] = group.Name
 	}
 
 	// If specific groups are named, use only those
-	if onlyGroups := d.Get("only_groups").(*schema.Set); onlyGroups.Len() > 0 {
+	if onlyGroups := get("only_groups").(*schema.Set); onlyGroups.Len() > 0 {
 		var selected []string
 		for _, name := range onlyGroups.List() {
 			groupName := name.(string)
@@ -692,7 +962,7 @@ func determineGroupsToManage(ruleGroups RuleGroups, d *schema.ResourceData) []st
 	}
 
 	// If ignore_groups is set, exclude those
-	if ignoreGroups := d.Get("ignore_groups").(*schema.Set); ignoreGroups.Len() > 0 {
+	if ignoreGroups := get("ignore_groups").(*schema.Set); ignoreGroups.Len() > 0 {
 		var ignored []string
 		for _, name := range ignoreGroups.List() {
 			ignored = append(ignored, name.(string))
@@ -711,7 +981,9 @@ func determineGroupsToManage(ruleGroups RuleGroups, d *schema.ResourceData) []st
 	return allGroupNames
 }
 
-func setComputedFields(d *schema.ResourceData, ruleGroups RuleGroups, managedGroups []string) {
+// setComputedFields sets the computed fields of the managed groups, applied holding the hash
+// of each group as last written to Mimir.
+func setComputedFields(d *schema.ResourceData, ruleGroups RuleGroups, managedGroups []string, applied map[string]string) {
 	// Set managed_groups
 	d.Set("managed_groups", managedGroups)
 	d.Set("groups_count", len(managedGroups))
@@ -747,6 +1019,8 @@ func setComputedFields(d *schema.ResourceData, ruleGroups RuleGroups, managedGro
 			"rules_count":           len(group.Rules),
 			"alerting_rules_count":  alertingCount,
 			"recording_rules_count": recordingCount,
+			"file":                  group.file,
+			"hash":                  applied[group.Name],
 		}
 		groupDetails = append(groupDetails, groupDetail)
 	}
@@ -760,22 +1034,141 @@ func setComputedFields(d *schema.ResourceData, ruleGroups RuleGroups, managedGro
 	d.Set("content_hash", contentHash)
 }
 
-func calculateContentHash(ruleGroups RuleGroups, managedGroups []string) string {
-	// Create a subset of rule groups that are actually managed
+// normalizeRuleGroupsContent renders rule groups the way the provider marshals them, so
+// that two YAML documents describing the same groups compare equal.
+func normalizeRuleGroupsContent(ruleGroups RuleGroups) (string, error) {
+	data, err := yaml.Marshal(ruleGroups)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal rule groups to YAML: %w", err)
+	}
+	return string(data), nil
+}
+
+// suppressEquivalentRuleGroupsContent hides a 'content' diff between two YAML documents that
+// describe the same rule groups, e.g. the normalised content set on import and the
+// hand-written YAML of the configuration.
+func suppressEquivalentRuleGroupsContent(_, oldValue, newValue string, d *schema.ResourceData) bool {
+	if d == nil {
+		return equivalentRuleGroupsContent(oldValue, newValue, nil)
+	}
+	return equivalentRuleGroupsContent(oldValue, newValue, d.Get)
+}
+
+// equivalentRuleGroupsContent reports whether two contents hold the same managed rule
+// groups, the configuration being read through get (d.Get or diff.Get) when not nil.
+func equivalentRuleGroupsContent(oldValue, newValue string, get func(string) interface{}) bool {
+	if oldValue == "" || newValue == "" {
+		return false
+	}
+	if get != nil && get("content_format").(string) == ruleContentFormatPrometheusRule {
+		// Compare the whole manifests, whose metadata may be mapped to the namespace
+		oldRules, err := parsePrometheusRules([]byte(oldValue))
+		if err != nil {
+			return false
+		}
+		newRules, err := parsePrometheusRules([]byte(newValue))
+		if err != nil {
+			return false
+		}
+		oldContent, err := yaml.Marshal(oldRules)
+		if err != nil {
+			return false
+		}
+		newContent, err := yaml.Marshal(newRules)
+		return err == nil && string(oldContent) == string(newContent)
+	}
+	oldGroups, err := parseRuleGroupsContent([]byte(oldValue), ruleContentOptions{format: ruleContentFormatRules})
+	if err != nil {
+		return false
+	}
+	newGroups, err := parseRuleGroupsContent([]byte(newValue), ruleContentOptions{format: ruleContentFormatRules})
+	if err != nil {
+		return false
+	}
+	if get != nil {
+		// Groups left out by only_groups or ignore_groups are not managed: an imported
+		// content holding the whole namespace matches a configuration holding the others.
+		oldGroups = filterManagedGroups(oldGroups, determineGroupsToManage(oldGroups, get))
+		newGroups = filterManagedGroups(newGroups, determineGroupsToManage(newGroups, get))
+	}
+	oldContent, err := normalizeRuleGroupsContent(oldGroups)
+	if err != nil {
+		return false
+	}
+	newContent, err := normalizeRuleGroupsContent(newGroups)
+	return err == nil && oldContent == newContent
+}
+
+// filterManagedGroups returns the subset of rule groups that are actually managed.
+func filterManagedGroups(ruleGroups RuleGroups, managedGroups []string) RuleGroups {
 	managedRuleGroups := RuleGroups{}
 	for _, group := range ruleGroups.Groups {
 		if contains(managedGroups, group.Name) {
 			managedRuleGroups.Groups = append(managedRuleGroups.Groups, group)
 		}
 	}
+	return managedRuleGroups
+}
 
-	data, _ := yaml.Marshal(managedRuleGroups)
+func calculateContentHash(ruleGroups RuleGroups, managedGroups []string) string {
+	data, _ := yaml.Marshal(filterManagedGroups(ruleGroups, managedGroups))
 	h := sha256.New()
 	h.Write(data)
 	return fmt.Sprintf("%x", h.Sum(nil))
 }
 
-func createRuleGroup(client *apiClient, namespace, orgID string, group RuleGroup) error {
+// ruleGroupHash returns the hash of a rule group as written to Mimir.
+func ruleGroupHash(group RuleGroup) string {
+	data, _ := yaml.Marshal(group)
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
+
+// ruleGroupHashes returns the hash of each rule group, keyed by name.
+func ruleGroupHashes(ruleGroups RuleGroups) map[string]string {
+	hashes := make(map[string]string, len(ruleGroups.Groups))
+	for _, group := range ruleGroups.Groups {
+		hashes[group.Name] = ruleGroupHash(group)
+	}
+	return hashes
+}
+
+// appliedRuleGroupHashes returns the hash of each group of the computed groups, keyed by
+// name. The hash is empty for groups tracked before hashes were recorded, or imported.
+func appliedRuleGroupHashes(groups interface{}) map[string]string {
+	hashes := make(map[string]string)
+	list, _ := groups.([]interface{})
+	for _, raw := range list {
+		group, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := group["name"].(string)
+		hash, _ := group["hash"].(string)
+		hashes[name] = hash
+	}
+	return hashes
+}
+
+// pendingRuleGroups returns the groups a previous apply did not fully write or delete: the
+// desired groups which are not tracked or were last written with another content, and the
+// tracked groups no longer desired. Groups tracked without a hash are assumed up to date.
+func pendingRuleGroups(desired, applied map[string]string, tracked []string) []string {
+	var pending []string
+	for name, hash := range desired {
+		if h, ok := applied[name]; !ok || (h != "" && h != hash) {
+			pending = append(pending, name)
+		}
+	}
+	for _, name := range tracked {
+		if _, ok := desired[name]; !ok {
+			pending = append(pending, name)
+		}
+	}
+	sort.Strings(pending)
+	return pending
+}
+
+func createRuleGroup(ctx context.Context, client *apiClient, namespace, orgID string, group RuleGroup) error {
 	headers := map[string]string{contentTypeHeader: contentTypeYAML}
 	if orgID != "" {
 		headers["X-Scope-OrgID"] = orgID
@@ -788,18 +1181,18 @@ func createRuleGroup(client *apiClient, namespace, orgID string, group RuleGroup
 	}
 
 	path := rulesNamespacePath(namespace)
-	_, err = client.sendRequest("ruler", "POST", path, string(yamlData), headers)
+	_, err = client.sendRequest(ctx, "ruler", "POST", path, string(yamlData), headers)
 	return err
 }
 
-func deleteRuleGroup(client *apiClient, namespace, orgID, groupName string) error {
+func deleteRuleGroup(ctx context.Context, client *apiClient, namespace, orgID, groupName string) error {
 	headers := make(map[string]string)
 	if orgID != "" {
 		headers["X-Scope-OrgID"] = orgID
 	}
 
 	path := rulesGroupPath(namespace, groupName)
-	_, err := client.sendRequest("ruler", "DELETE", path, "", headers)
+	_, err := client.sendRequest(ctx, "ruler", "DELETE", path, "", headers)
 	if err != nil && strings.Contains(err.Error(), "response code '404'") {
 		// Group already doesn't exist, consider this success
 		return nil
@@ -0,0 +1,348 @@
+package mimir
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// Series deletion API of the Cortex purger, served under the Prometheus HTTP prefix.
+const (
+	apiDeleteSeriesPath        = "/api/v1/admin/tsdb/delete_series"
+	apiCancelDeleteRequestPath = "/api/v1/admin/tsdb/cancel_delete_request"
+
+	// deleteRequestStatusReceived is the only status in which a request can be cancelled.
+	deleteRequestStatusReceived = "received"
+)
+
+type deleteRequest struct {
+	RequestID string     `json:"request_id"`
+	StartTime model.Time `json:"start_time"`
+	EndTime   model.Time `json:"end_time"`
+	Selectors []string   `json:"selectors"`
+	Status    string     `json:"status"`
+	CreatedAt model.Time `json:"created_at"`
+}
+
+func resourcemimirSeriesDeletionRequest() *schema.Resource {
+	return &schema.Resource{
+		Description: "Submits a request to delete the series matching some selectors, e.g. to purge leaked data. " +
+			"Destroying the resource cancels the request while it has not started being processed.",
+		CreateContext: resourcemimirSeriesDeletionRequestCreate,
+		ReadContext:   resourcemimirSeriesDeletionRequestRead,
+		UpdateContext: resourcemimirSeriesDeletionRequestUpdate,
+		DeleteContext: resourcemimirSeriesDeletionRequestDelete,
+		CustomizeDiff: resourcemimirSeriesDeletionRequestCustomizeDiff,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			orgIDKey: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Description:  orgIDDescription,
+				ValidateFunc: validateOrgID,
+			},
+			"matchers": {
+				Type:        schema.TypeList,
+				Required:    true,
+				ForceNew:    true,
+				MinItems:    1,
+				Description: "Series selectors of the series to delete, e.g. `{__name__=\"http_requests_total\", user_email!=\"\"}`.",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateSeriesSelector,
+				},
+			},
+			"start": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Description:  "Start of the deletion range, as a RFC3339 timestamp or a unix timestamp. Defaults to the oldest sample.",
+				ValidateFunc: validateQueryTime,
+			},
+			"end": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Description:  "End of the deletion range, as a RFC3339 timestamp or a unix timestamp. Defaults to the time of the request.",
+				ValidateFunc: validateQueryTime,
+			},
+			"allow_match_all": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Allow a selector matching every series of the tenant. Refused by default, as it would delete the whole tenant.",
+			},
+			"request_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "ID of the delete request.",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Status of the delete request: `received`, `buildingPlan`, `deleting` or `processed`.",
+			},
+			"created_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Time the delete request was created, as a RFC3339 timestamp.",
+			},
+			"request_headers": requestHeadersSchema("resource"),
+			"auth":            requestAuthSchema("resource"),
+		}, /* End schema */
+	}
+}
+
+func resourcemimirSeriesDeletionRequestCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	if diff.Get("allow_match_all").(bool) {
+		return nil
+	}
+	return checkSeriesSelectorsScope(expandStringArray(diff.Get("matchers").([]interface{})))
+}
+
+func resourcemimirSeriesDeletionRequestCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).forResource(d)
+	orgID := d.Get(orgIDKey).(string)
+	matchers := expandStringArray(d.Get("matchers").([]interface{}))
+
+	// Guard against a config that bypassed plan-time validation.
+	if !d.Get("allow_match_all").(bool) {
+		if err := checkSeriesSelectorsScope(matchers); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	headers := map[string]string{"Content-Type": "application/x-www-form-urlencoded"}
+	if orgID != "" {
+		headers["X-Scope-OrgID"] = orgID
+	}
+	params := url.Values{}
+	for _, m := range matchers {
+		params.Add("match[]", m)
+	}
+	for _, k := range []string{"start", "end"} {
+		if v := d.Get(k).(string); v != "" {
+			params.Set(k, v)
+		}
+	}
+
+	_, err := client.sendRequest(ctx, "query_frontend", "POST", apiDeleteSeriesPath, params.Encode(), headers)
+	baseMsg := "Cannot create series delete request"
+	err = handleHTTPError(err, baseMsg)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// The API does not return the ID of the new request; look it up in the request list.
+	requests, err := client.listDeleteRequests(ctx, orgID)
+	baseMsg = "Cannot read series delete requests"
+	err = handleHTTPError(err, baseMsg)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	request := findDeleteRequest(requests, matchers, d.Get("start").(string), d.Get("end").(string))
+	if request == nil {
+		return diag.Errorf("series delete request created, but not found in the delete requests of the tenant")
+	}
+
+	d.SetId(request.RequestID)
+	return resourcemimirSeriesDeletionRequestRead(ctx, d, meta)
+}
+
+func resourcemimirSeriesDeletionRequestRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).forResource(d)
+	orgID := d.Get(orgIDKey).(string)
+
+	requests, err := client.listDeleteRequests(ctx, orgID)
+	baseMsg := "Cannot read series delete requests"
+	err = handleHTTPError(err, baseMsg)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for _, request := range requests {
+		if request.RequestID != d.Id() {
+			continue
+		}
+		if err := d.Set("request_id", request.RequestID); err != nil {
+			return diag.FromErr(err)
+		}
+		if err := d.Set("status", request.Status); err != nil {
+			return diag.FromErr(err)
+		}
+		if err := d.Set("created_at", request.CreatedAt.Time().UTC().Format(time.RFC3339)); err != nil {
+			return diag.FromErr(err)
+		}
+		return nil
+	}
+
+	tflog.Warn(ctx, "Series delete request not found, removing from state", map[string]interface{}{
+		"request_id": d.Id(),
+	})
+	d.SetId("")
+	return nil
+}
+
+func resourcemimirSeriesDeletionRequestUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	// Only request_headers and auth can change in place.
+	return resourcemimirSeriesDeletionRequestRead(ctx, d, meta)
+}
+
+func resourcemimirSeriesDeletionRequestDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).forResource(d)
+	orgID := d.Get(orgIDKey).(string)
+
+	// The request may have progressed since the last refresh.
+	if diags := resourcemimirSeriesDeletionRequestRead(ctx, d, meta); diags.HasError() || d.Id() == "" {
+		return diags
+	}
+	if status := d.Get("status").(string); status != deleteRequestStatusReceived {
+		tflog.Warn(ctx, "Series delete request can no longer be cancelled, removing it from the state only", map[string]interface{}{
+			"request_id": d.Id(),
+			"status":     status,
+		})
+		d.SetId("")
+		return nil
+	}
+
+	headers := map[string]string{"Content-Type": "application/x-www-form-urlencoded"}
+	if orgID != "" {
+		headers["X-Scope-OrgID"] = orgID
+	}
+	params := url.Values{"request_id": {d.Id()}}
+	_, err := client.sendRequest(ctx, "query_frontend", "POST", apiCancelDeleteRequestPath, params.Encode(), headers)
+	baseMsg := fmt.Sprintf("Cannot cancel series delete request %s", d.Id())
+	err = handleHTTPError(err, baseMsg)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func (client *apiClient) listDeleteRequests(ctx context.Context, orgID string) ([]deleteRequest, error) {
+	headers := make(map[string]string)
+	if orgID != "" {
+		headers["X-Scope-OrgID"] = orgID
+	}
+	jobraw, err := client.sendRequest(ctx, "query_frontend", "GET", apiDeleteSeriesPath, "", headers)
+	if err != nil {
+		return nil, err
+	}
+
+	var requests []deleteRequest
+	if err := json.Unmarshal([]byte(jobraw), &requests); err != nil {
+		return nil, fmt.Errorf("unable to decode series delete requests: %v", err)
+	}
+	return requests, nil
+}
+
+// findDeleteRequest returns the newest request with the given selectors and range. An empty
+// start or end matches any value, as the server fills them with its defaults.
+func findDeleteRequest(requests []deleteRequest, matchers []string, start, end string) *deleteRequest {
+	want := normalizeSelectors(matchers)
+	var found *deleteRequest
+	for i, request := range requests {
+		if normalizeSelectors(request.Selectors) != want {
+			continue
+		}
+		if start != "" && !sameRequestTime(request.StartTime, start) {
+			continue
+		}
+		if end != "" && !sameRequestTime(request.EndTime, end) {
+			continue
+		}
+		if found == nil || request.CreatedAt.After(found.CreatedAt) {
+			found = &requests[i]
+		}
+	}
+	return found
+}
+
+func sameRequestTime(t model.Time, value string) bool {
+	parsed, err := parseQueryTime(value)
+	return err == nil && model.TimeFromUnixNano(parsed.UnixNano()) == t
+}
+
+// normalizeSelectors returns the selectors in their canonical form, sorted, so selectors
+// echoed back by the server compare equal to the configured ones.
+func normalizeSelectors(selectors []string) string {
+	normalized := make([]string, 0, len(selectors))
+	for _, s := range selectors {
+		if matchers, err := parser.NewParser(parser.Options{}).ParseMetricSelector(s); err == nil {
+			sort.Slice(matchers, func(i, j int) bool { return matchers[i].String() < matchers[j].String() })
+			parts := make([]string, 0, len(matchers))
+			for _, m := range matchers {
+				parts = append(parts, m.String())
+			}
+			s = "{" + strings.Join(parts, ",") + "}"
+		}
+		normalized = append(normalized, s)
+	}
+	sort.Strings(normalized)
+	return strings.Join(normalized, "\n")
+}
+
+func validateSeriesSelector(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+
+	if _, err := parser.NewParser(parser.Options{}).ParseMetricSelector(value); err != nil {
+		errors = append(errors, fmt.Errorf(
+			"\"%s\": Invalid series selector %q: %v", k, value, err))
+	}
+
+	return
+}
+
+// checkSeriesSelectorsScope rejects selectors matching every series of the tenant.
+func checkSeriesSelectorsScope(selectors []string) error {
+	if len(selectors) == 0 {
+		return fmt.Errorf("at least one series selector is required")
+	}
+	for _, s := range selectors {
+		matchers, err := parser.NewParser(parser.Options{}).ParseMetricSelector(s)
+		if err != nil {
+			return fmt.Errorf("invalid series selector %q: %v", s, err)
+		}
+		if matchesAllSeries(matchers) {
+			return fmt.Errorf("series selector %q matches every series of the tenant, set allow_match_all to delete them", s)
+		}
+	}
+	return nil
+}
+
+// matchesAllSeries reports whether every matcher accepts any value of its label, e.g.
+// {__name__=~".+"}.
+func matchesAllSeries(matchers []*labels.Matcher) bool {
+	for _, m := range matchers {
+		switch {
+		case m.Matches(""):
+		case m.Type == labels.MatchNotEqual && m.Value == "":
+		case m.Type == labels.MatchRegexp && (m.Value == ".+" || m.Value == ".*"):
+		case m.Type == labels.MatchNotRegexp && m.Value == "":
+		default:
+			return false
+		}
+	}
+	return true
+}
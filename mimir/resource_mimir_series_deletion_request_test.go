@@ -0,0 +1,144 @@
+package mimir
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/prometheus/common/model"
+)
+
+func TestCheckSeriesSelectorsScope(t *testing.T) {
+	for selector, valid := range map[string]bool{
+		`{__name__="http_requests_total",user_email!=""}`: true,
+		`http_requests_total`:                             true,
+		`{job="api"}`:                                     true,
+		`{__name__=~".+"}`:                                false,
+		`{__name__=~".*",job!=""}`:                        false,
+		`{job=~".+",instance=~".*"}`:                      false,
+		`{job!~""}`:                                       false,
+		`{__name__=~".+",job="api"}`:                      true,
+		`{job=~"api|web"}`:                                true,
+		`{job="api"`:                                      false,
+	} {
+		if err := checkSeriesSelectorsScope([]string{selector}); (err == nil) != valid {
+			t.Errorf("checkSeriesSelectorsScope(%q) = %v", selector, err)
+		}
+	}
+	if err := checkSeriesSelectorsScope(nil); err == nil {
+		t.Error("expected an error without selectors")
+	}
+}
+
+func TestFindDeleteRequest(t *testing.T) {
+	requests := []deleteRequest{
+		{RequestID: "old", Selectors: []string{`{user_email!="",__name__="http_requests_total"}`}, StartTime: 1700000000000, CreatedAt: 1},
+		{RequestID: "new", Selectors: []string{`{__name__="http_requests_total",user_email!=""}`}, StartTime: 1700000000000, CreatedAt: 2},
+		{RequestID: "other-start", Selectors: []string{`{__name__="http_requests_total",user_email!=""}`}, StartTime: 1600000000000, CreatedAt: 3},
+		{RequestID: "other-selector", Selectors: []string{`{job="api"}`}, StartTime: 1700000000000, CreatedAt: 4},
+	}
+	matchers := []string{`http_requests_total{user_email!=""}`}
+
+	if r := findDeleteRequest(requests, matchers, "2023-11-14T22:13:20Z", ""); r == nil || r.RequestID != "new" {
+		t.Errorf("expected the newest matching request, got %v", r)
+	}
+	if r := findDeleteRequest(requests, matchers, "1600000000", ""); r == nil || r.RequestID != "other-start" {
+		t.Errorf("expected the request with the unix start, got %v", r)
+	}
+	if r := findDeleteRequest(requests, []string{`{job="web"}`}, "", ""); r != nil {
+		t.Errorf("expected no request, got %v", r)
+	}
+}
+
+func TestSeriesDeletionRequestLifecycle(t *testing.T) {
+	var mu sync.Mutex
+	var requests []deleteRequest
+	cancelled := ""
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Header.Get("X-Scope-OrgID") != "tenant-1" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch {
+		case r.URL.Path == "/prometheus"+apiDeleteSeriesPath && r.Method == "POST":
+			if err := r.ParseForm(); err != nil || r.PostForm.Get("start") != "1700000000" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			requests = append(requests, deleteRequest{
+				RequestID: "req-1",
+				Selectors: r.PostForm["match[]"],
+				StartTime: model.TimeFromUnix(1700000000),
+				EndTime:   model.TimeFromUnix(1800000000),
+				Status:    deleteRequestStatusReceived,
+				CreatedAt: model.TimeFromUnix(1800000000),
+			})
+			w.WriteHeader(http.StatusNoContent)
+		case r.URL.Path == "/prometheus"+apiDeleteSeriesPath && r.Method == "GET":
+			_ = json.NewEncoder(w).Encode(requests)
+		case r.URL.Path == "/prometheus"+apiCancelDeleteRequestPath:
+			cancelled = r.FormValue("request_id")
+			requests = nil
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := NewAPIClient(&apiClientOpt{uri: srv.URL, queryFrontendURI: srv.URL + "/prometheus", headers: map[string]string{}, timeout: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := schema.TestResourceDataRaw(t, resourcemimirSeriesDeletionRequest().Schema, map[string]interface{}{
+		orgIDKey:   "tenant-1",
+		"matchers": []interface{}{`{__name__="http_requests_total",user_email!=""}`},
+		"start":    "1700000000",
+	})
+	if diags := resourcemimirSeriesDeletionRequestCreate(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+	if d.Id() != "req-1" || d.Get("status") != deleteRequestStatusReceived || d.Get("created_at") != "2027-01-15T08:00:00Z" {
+		t.Errorf("unexpected state: %v", d.State())
+	}
+
+	if diags := resourcemimirSeriesDeletionRequestDelete(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("delete: %v", diags)
+	}
+	if cancelled != "req-1" || d.Id() != "" {
+		t.Errorf("expected req-1 to be cancelled, got %q", cancelled)
+	}
+}
+
+func TestSeriesDeletionRequestDeleteProcessing(t *testing.T) {
+	canceled := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, apiCancelDeleteRequestPath) {
+			canceled = true
+		}
+		_ = json.NewEncoder(w).Encode([]deleteRequest{{RequestID: "req-1", Status: "deleting"}})
+	}))
+	defer srv.Close()
+
+	client, err := NewAPIClient(&apiClientOpt{uri: srv.URL, headers: map[string]string{}, timeout: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := schema.TestResourceDataRaw(t, resourcemimirSeriesDeletionRequest().Schema, map[string]interface{}{
+		"matchers": []interface{}{`{job="api"}`},
+	})
+	d.SetId("req-1")
+	if diags := resourcemimirSeriesDeletionRequestDelete(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("delete: %v", diags)
+	}
+	if canceled || d.Id() != "" {
+		t.Error("a request being processed must only be removed from the state")
+	}
+}
@@ -0,0 +1,248 @@
+package mimir
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Tenant deletion admin endpoints, served at the root like /api/v1/user_limits.
+const (
+	apiCompactorDeleteTenantPath          = "/compactor/delete_tenant"
+	apiCompactorDeleteTenantStatusPath    = "/compactor/delete_tenant_status"
+	apiRulerDeleteTenantConfigPath        = "/ruler/delete_tenant_config"
+	apiAlertmanagerDeleteTenantConfigPath = "/multitenant_alertmanager/delete_tenant_config"
+)
+
+// tenantDeletionPollInterval is the delay between two compactor deletion status checks.
+var tenantDeletionPollInterval = 30 * time.Second
+
+type tenantDeletionStatus struct {
+	TenantID      string `json:"tenant_id"`
+	BlocksDeleted bool   `json:"blocks_deleted"`
+}
+
+func resourcemimirTenantDeletion() *schema.Resource {
+	return &schema.Resource{
+		Description: "Deletes all the data of a tenant: its blocks (through the compactor), its ruler config and its alertmanager config. " +
+			"The deletion is irreversible and happens on create; destroying the resource only removes it from the state.",
+		CreateContext: resourcemimirTenantDeletionCreate,
+		ReadContext:   resourcemimirTenantDeletionRead,
+		UpdateContext: resourcemimirTenantDeletionUpdate,
+		DeleteContext: resourcemimirTenantDeletionDelete,
+		CustomizeDiff: resourcemimirTenantDeletionCustomizeDiff,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(time.Hour),
+		},
+
+		Schema: map[string]*schema.Schema{
+			orgIDKey: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The Organization ID of the tenant to delete. Unlike other resources, it never defaults to the provider Org ID.",
+				ValidateFunc: validateOrgID,
+			},
+			"confirm_org_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Must be set to the same value as `org_id`, to confirm the deletion.",
+			},
+			"delete_ruler_config": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     true,
+				Description: "Delete the ruler config of the tenant. Disable it when the ruler is not deployed.",
+			},
+			"delete_alertmanager_config": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     true,
+				Description: "Delete the alertmanager config of the tenant. Disable it when the alertmanager is not deployed.",
+			},
+			"wait_for_blocks_deletion": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Wait on create until the compactor has deleted the blocks of the tenant, up to the create timeout.",
+			},
+			"requested_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Time the deletion was requested, as a RFC3339 timestamp.",
+			},
+			"ruler_config_deleted": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the ruler config of the tenant was deleted.",
+			},
+			"alertmanager_config_deleted": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the alertmanager config of the tenant was deleted.",
+			},
+			"blocks_deleted": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the compactor reports the blocks of the tenant as deleted. Refreshed on every read.",
+			},
+			"request_headers": requestHeadersSchema("resource"),
+			"auth":            requestAuthSchema("resource"),
+		}, /* End schema */
+	}
+}
+
+func resourcemimirTenantDeletionCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	orgID := diff.Get(orgIDKey).(string)
+	if confirm := diff.Get("confirm_org_id").(string); confirm != orgID {
+		return fmt.Errorf("confirm_org_id %q does not match org_id %q", confirm, orgID)
+	}
+	return nil
+}
+
+func resourcemimirTenantDeletionCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).forResource(d)
+	orgID := d.Get(orgIDKey).(string)
+
+	// Guard against a state or config that bypassed plan-time validation.
+	if orgID == "" || !validOrgID(orgID) || d.Get("confirm_org_id").(string) != orgID {
+		return diag.Errorf("refusing to delete tenant %q: org_id must be a valid Org ID matching confirm_org_id", orgID)
+	}
+	headers := map[string]string{"X-Scope-OrgID": orgID}
+
+	// The configs are deleted first: these requests are idempotent, so a failure leaves
+	// nothing in the state and the next apply retries them with the blocks deletion.
+	if d.Get("delete_ruler_config").(bool) {
+		_, err := client.sendRootRequest(ctx, "ruler", "POST", apiRulerDeleteTenantConfigPath, "", headers)
+		baseMsg := fmt.Sprintf("Cannot delete ruler config of tenant '%s' -", orgID)
+		err = handleHTTPError(err, baseMsg)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if err := d.Set("ruler_config_deleted", true); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.Get("delete_alertmanager_config").(bool) {
+		_, err := client.sendRootRequest(ctx, "alertmanager", "POST", apiAlertmanagerDeleteTenantConfigPath, "", headers)
+		baseMsg := fmt.Sprintf("Cannot delete alertmanager config of tenant '%s' -", orgID)
+		err = handleHTTPError(err, baseMsg)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if err := d.Set("alertmanager_config_deleted", true); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	_, err := client.sendRequest(ctx, "compactor", "POST", apiCompactorDeleteTenantPath, "", headers)
+	baseMsg := fmt.Sprintf("Cannot request blocks deletion of tenant '%s' -", orgID)
+	err = handleHTTPError(err, baseMsg)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(orgID)
+	if err := d.Set("requested_at", time.Now().UTC().Format(time.RFC3339)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.Get("wait_for_blocks_deletion").(bool) {
+		if err := waitForTenantBlocksDeletion(ctx, client, orgID); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourcemimirTenantDeletionRead(ctx, d, meta)
+}
+
+func resourcemimirTenantDeletionRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*apiClient).forResource(d)
+
+	status, err := client.fetchTenantDeletionStatus(ctx, d.Id())
+	baseMsg := fmt.Sprintf("Cannot read deletion status of tenant '%s' -", d.Id())
+	err = handleHTTPError(err, baseMsg)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set(orgIDKey, d.Id()); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("blocks_deleted", status.BlocksDeleted); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func resourcemimirTenantDeletionUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	// Only wait_for_blocks_deletion, request_headers and auth can change in place; the
+	// deletion itself only happens on create.
+	return resourcemimirTenantDeletionRead(ctx, d, meta)
+}
+
+func resourcemimirTenantDeletionDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Warn(ctx, "Tenant deletion is irreversible, removing it from the state only", map[string]interface{}{"org_id": d.Id()})
+	d.SetId("")
+	return nil
+}
+
+func (client *apiClient) fetchTenantDeletionStatus(ctx context.Context, orgID string) (*tenantDeletionStatus, error) {
+	jobraw, err := client.sendRequest(ctx, "compactor", "GET", apiCompactorDeleteTenantStatusPath, "", map[string]string{"X-Scope-OrgID": orgID})
+	if err != nil {
+		return nil, err
+	}
+
+	var status tenantDeletionStatus
+	if err := json.Unmarshal([]byte(jobraw), &status); err != nil {
+		return nil, fmt.Errorf("unable to decode tenant deletion status: %v", err)
+	}
+	return &status, nil
+}
+
+// waitForTenantBlocksDeletion polls the compactor until the blocks of the tenant are
+// deleted. The compactor deletes them on its next cleanup cycle, which can take a while.
+// It gives up when ctx is done, whose deadline is the create timeout.
+func waitForTenantBlocksDeletion(ctx context.Context, client *apiClient, orgID string) error {
+	timeoutErr := fmt.Errorf("timeout waiting for the blocks of tenant '%s' to be deleted, the compactor keeps deleting them in the background", orgID)
+	for {
+		status, err := client.fetchTenantDeletionStatus(ctx, orgID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return contextError(ctx, timeoutErr)
+			}
+			return handleHTTPError(err, fmt.Sprintf("Cannot read deletion status of tenant '%s' -", orgID))
+		}
+		if status.BlocksDeleted {
+			return nil
+		}
+		tflog.Debug(ctx, "Blocks of tenant not deleted yet", map[string]interface{}{
+			"org_id":        orgID,
+			"poll_interval": tenantDeletionPollInterval.String(),
+		})
+
+		select {
+		case <-ctx.Done():
+			return contextError(ctx, timeoutErr)
+		case <-time.After(tenantDeletionPollInterval):
+		}
+	}
+}
+
+// contextError returns timeoutErr if the deadline of ctx is exceeded, and the error of
+// ctx otherwise, e.g. when terraform is interrupted.
+func contextError(ctx context.Context, timeoutErr error) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return timeoutErr
+	}
+	return ctx.Err()
+}
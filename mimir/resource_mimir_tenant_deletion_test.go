@@ -0,0 +1,218 @@
+package mimir
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// newTenantDeletionTestServer stands in for Mimir: the blocks of a tenant are reported as
+// deleted after deletedAfter status checks following the deletion request.
+func newTenantDeletionTestServer(t *testing.T, deletedAfter int) (*apiClient, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var calls []string
+	statusChecks := 0
+	requested := false
+
+	srv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		orgID := r.Header.Get("X-Scope-OrgID")
+		calls = append(calls, r.Method+" "+r.URL.Path+" "+orgID)
+		if orgID != "tenant-1" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch r.URL.Path {
+		case apiCompactorDeleteTenantPath:
+			requested = true
+		case apiRulerDeleteTenantConfigPath, apiAlertmanagerDeleteTenantConfigPath:
+		case apiCompactorDeleteTenantStatusPath:
+			if requested {
+				statusChecks++
+			}
+			deleted := requested && statusChecks > deletedAfter
+			if deleted {
+				_, _ = w.Write([]byte(`{"tenant_id":"tenant-1","blocks_deleted":true}`))
+			} else {
+				_, _ = w.Write([]byte(`{"tenant_id":"tenant-1","blocks_deleted":false}`))
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	client := newTestClient(t, &apiClientOpt{uri: srv.URL, headers: map[string]string{"X-Scope-OrgID": "provider-tenant"}, timeout: 2})
+	return client, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), calls...)
+	}
+}
+
+func TestTenantDeletionCreate(t *testing.T) {
+	defer func(d time.Duration) { tenantDeletionPollInterval = d }(tenantDeletionPollInterval)
+	tenantDeletionPollInterval = time.Millisecond
+
+	client, calls := newTenantDeletionTestServer(t, 2)
+	d := schema.TestResourceDataRaw(t, resourcemimirTenantDeletion().Schema, map[string]interface{}{
+		orgIDKey:         "tenant-1",
+		"confirm_org_id": "tenant-1",
+	})
+	if diags := resourcemimirTenantDeletionCreate(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+
+	if d.Id() != "tenant-1" || !d.Get("blocks_deleted").(bool) || !d.Get("ruler_config_deleted").(bool) ||
+		!d.Get("alertmanager_config_deleted").(bool) || d.Get("requested_at") == "" {
+		t.Errorf("unexpected state: %v", d.State())
+	}
+	got := strings.Join(calls(), "\n")
+	want := strings.Join([]string{
+		"POST " + apiRulerDeleteTenantConfigPath + " tenant-1",
+		"POST " + apiAlertmanagerDeleteTenantConfigPath + " tenant-1",
+		"POST " + apiCompactorDeleteTenantPath + " tenant-1",
+		"GET " + apiCompactorDeleteTenantStatusPath + " tenant-1",
+		"GET " + apiCompactorDeleteTenantStatusPath + " tenant-1",
+		"GET " + apiCompactorDeleteTenantStatusPath + " tenant-1",
+		"GET " + apiCompactorDeleteTenantStatusPath + " tenant-1",
+	}, "\n")
+	if got != want {
+		t.Errorf("unexpected requests:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestTenantDeletionCreateNoWait(t *testing.T) {
+	client, calls := newTenantDeletionTestServer(t, 5)
+	d := schema.TestResourceDataRaw(t, resourcemimirTenantDeletion().Schema, map[string]interface{}{
+		orgIDKey:                     "tenant-1",
+		"confirm_org_id":             "tenant-1",
+		"delete_ruler_config":        false,
+		"delete_alertmanager_config": false,
+		"wait_for_blocks_deletion":   false,
+	})
+	if diags := resourcemimirTenantDeletionCreate(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+	if d.Get("blocks_deleted").(bool) || d.Get("ruler_config_deleted").(bool) || d.Get("alertmanager_config_deleted").(bool) {
+		t.Errorf("unexpected state: %v", d.State())
+	}
+	if n := len(calls()); n != 2 {
+		t.Errorf("expected the deletion request and a status read, got %q", calls())
+	}
+}
+
+func TestTenantDeletionCreateRulerURI(t *testing.T) {
+	var rulerCalls []string
+	rulerSrv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rulerCalls = append(rulerCalls, r.Method+" "+r.URL.Path)
+		if r.URL.Path != apiRulerDeleteTenantConfigPath {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	// The admin endpoint is served at the root of the ruler, not under its API prefix.
+	client, calls := newTenantDeletionTestServer(t, 0)
+	client.rulerURI = rulerSrv.URL + "/prometheus"
+	d := schema.TestResourceDataRaw(t, resourcemimirTenantDeletion().Schema, map[string]interface{}{
+		orgIDKey:                     "tenant-1",
+		"confirm_org_id":             "tenant-1",
+		"delete_alertmanager_config": false,
+		"wait_for_blocks_deletion":   false,
+	})
+	if diags := resourcemimirTenantDeletionCreate(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+	if want := "POST " + apiRulerDeleteTenantConfigPath; len(rulerCalls) != 1 || rulerCalls[0] != want {
+		t.Errorf("expected %q on the ruler, got %q", want, rulerCalls)
+	}
+	for _, c := range calls() {
+		if strings.Contains(c, apiRulerDeleteTenantConfigPath) {
+			t.Errorf("unexpected ruler request on the provider uri: %q", c)
+		}
+	}
+}
+
+func TestTenantDeletionCreateRulerFailure(t *testing.T) {
+	client, calls := newTenantDeletionTestServer(t, 0)
+	client.rulerURI = "http://127.0.0.1:1"
+	d := schema.TestResourceDataRaw(t, resourcemimirTenantDeletion().Schema, map[string]interface{}{
+		orgIDKey:         "tenant-1",
+		"confirm_org_id": "tenant-1",
+	})
+	if diags := resourcemimirTenantDeletionCreate(context.Background(), d, client); !diags.HasError() {
+		t.Fatal("expected an error")
+	}
+	// Nothing is stored, so the next apply retries the whole deletion.
+	if d.Id() != "" || len(calls()) != 0 {
+		t.Errorf("expected no state and no blocks deletion request, got %q and %q", d.Id(), calls())
+	}
+}
+
+func TestTenantDeletionCreateTimeout(t *testing.T) {
+	defer func(d time.Duration) { tenantDeletionPollInterval = d }(tenantDeletionPollInterval)
+	tenantDeletionPollInterval = time.Millisecond
+
+	client, _ := newTenantDeletionTestServer(t, 1<<30)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := waitForTenantBlocksDeletion(ctx, client, "tenant-1")
+	if err == nil || !strings.Contains(err.Error(), "timeout") {
+		t.Errorf("expected a timeout, got %v", err)
+	}
+}
+
+func TestTenantDeletionCreateCancelled(t *testing.T) {
+	defer func(d time.Duration) { tenantDeletionPollInterval = d }(tenantDeletionPollInterval)
+	tenantDeletionPollInterval = time.Millisecond
+
+	client, _ := newTenantDeletionTestServer(t, 1<<30)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	err := waitForTenantBlocksDeletion(ctx, client, "tenant-1")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the cancellation error, got %v", err)
+	}
+}
+
+func TestTenantDeletionCreateRequiresConfirmation(t *testing.T) {
+	for _, tc := range []struct{ orgID, confirm string }{
+		{"tenant-1", "tenant-2"},
+		{"tenant-1", ""},
+		{"../tenant-1", "../tenant-1"},
+	} {
+		client, calls := newTenantDeletionTestServer(t, 0)
+		d := schema.TestResourceDataRaw(t, resourcemimirTenantDeletion().Schema, map[string]interface{}{
+			orgIDKey:         tc.orgID,
+			"confirm_org_id": tc.confirm,
+		})
+		if diags := resourcemimirTenantDeletionCreate(context.Background(), d, client); !diags.HasError() {
+			t.Errorf("%q/%q: expected an error", tc.orgID, tc.confirm)
+		}
+		if len(calls()) != 0 {
+			t.Errorf("%q/%q: unexpected requests %q", tc.orgID, tc.confirm, calls())
+		}
+	}
+}
+
+func TestTenantDeletionDeleteKeepsData(t *testing.T) {
+	client, calls := newTenantDeletionTestServer(t, 0)
+	d := schema.TestResourceDataRaw(t, resourcemimirTenantDeletion().Schema, map[string]interface{}{
+		orgIDKey:         "tenant-1",
+		"confirm_org_id": "tenant-1",
+	})
+	d.SetId("tenant-1")
+	if diags := resourcemimirTenantDeletionDelete(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("delete: %v", diags)
+	}
+	if d.Id() != "" || len(calls()) != 0 {
+		t.Errorf("delete must only remove the resource from the state, got requests %q", calls())
+	}
+}
@@ -1,6 +1,7 @@
 package mimir
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"testing"
@@ -66,7 +67,7 @@ func countRuleGroups(body string) (int, error) {
 // at a mis-escaped path, so this out-of-band count guards the escaping/update paths.
 func testAccCheckMimirNamespaceGroupCount(namespace string, want int, client *apiClient) resource.TestCheckFunc {
 	return func(_ *terraform.State) error {
-		body, err := client.sendRequest("ruler", "GET", rulesNamespacePath(namespace), "", map[string]string{})
+		body, err := client.sendRequest(context.Background(), "ruler", "GET", rulesNamespacePath(namespace), "", map[string]string{})
 		if err != nil {
 			return fmt.Errorf("listing namespace %q: %w", namespace, err)
 		}
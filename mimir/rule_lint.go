@@ -0,0 +1,401 @@
+package mimir
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/prometheus/common/model"
+)
+
+const (
+	ruleLintSeverityWarning = "warning"
+	ruleLintSeverityError   = "error"
+)
+
+// ruleLintPolicies holds the provider-level rule_lint policies, evaluated on every rule
+// group resource during plan.
+var ruleLintPolicies []ruleLintPolicy
+
+// ruleLintPolicy is a set of convention checks applied to the rules of matching namespaces.
+type ruleLintPolicy struct {
+	name                     string
+	severity                 string
+	namespaces               []*regexp.Regexp
+	requiredAlertLabels      []string
+	requiredAlertAnnotations []string
+	recordingRuleNameFormat  bool
+	minFor                   time.Duration
+	minForLabelSelector      map[string]*regexp.Regexp
+}
+
+// ruleLintFinding is a single policy violation.
+type ruleLintFinding struct {
+	Policy   string
+	Severity string
+	Group    string
+	Rule     string
+	Message  string
+}
+
+func (f ruleLintFinding) String() string {
+	return fmt.Sprintf("[%s] group '%s', rule '%s': %s", f.Policy, f.Group, f.Rule, f.Message)
+}
+
+func ruleLintPolicySchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Policy name, used in findings. Defaults to `rule_lint[<index>]`.",
+			},
+			"severity": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      ruleLintSeverityError,
+				Description:  "Whether a violation of this policy fails the plan (`error`) or is only reported (`warning`).",
+				ValidateFunc: validation.StringInSlice([]string{ruleLintSeverityWarning, ruleLintSeverityError}, false),
+			},
+			"namespaces": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Regular expressions (fully anchored) of the namespaces this policy applies to. Applies to all namespaces if not set.",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringIsValidRegExp,
+				},
+			},
+			"required_alert_labels": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Labels every alerting rule must define, e.g. `severity`.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"required_alert_annotations": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Annotations every alerting rule must define, e.g. `runbook_url`.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"recording_rule_name_format": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Require recording rule names to follow the `level:metric:operations` convention.",
+			},
+			"min_for": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Minimum `for` duration of alerting rules (matching `min_for_label_selector` if set).",
+				ValidateFunc: validateDuration,
+			},
+			"min_for_label_selector": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: "Only apply `min_for` to alerting rules whose labels match all these regular expressions (fully anchored), e.g. `{ severity = \"page|critical\" }`.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func expandRuleLintPolicies(v []interface{}) ([]ruleLintPolicy, error) {
+	var policies []ruleLintPolicy
+	for i, raw := range v {
+		data, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		policy := ruleLintPolicy{
+			name:                     data["name"].(string),
+			severity:                 data["severity"].(string),
+			requiredAlertLabels:      expandStringArray(data["required_alert_labels"].([]interface{})),
+			requiredAlertAnnotations: expandStringArray(data["required_alert_annotations"].([]interface{})),
+			recordingRuleNameFormat:  data["recording_rule_name_format"].(bool),
+		}
+		if policy.name == "" {
+			policy.name = fmt.Sprintf("rule_lint[%d]", i)
+		}
+		if policy.severity == "" {
+			policy.severity = ruleLintSeverityError
+		}
+		for _, ns := range expandStringArray(data["namespaces"].([]interface{})) {
+			re, err := regexp.Compile("^(?:" + ns + ")$")
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid namespace regex %q: %v", policy.name, ns, err)
+			}
+			policy.namespaces = append(policy.namespaces, re)
+		}
+		if minFor := data["min_for"].(string); minFor != "" {
+			d, err := model.ParseDuration(minFor)
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid min_for %q: %v", policy.name, minFor, err)
+			}
+			policy.minFor = time.Duration(d)
+		}
+		for label, expr := range expandStringMap(data["min_for_label_selector"].(map[string]interface{})) {
+			re, err := regexp.Compile("^(?:" + expr + ")$")
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid min_for_label_selector regex %q for label %q: %v", policy.name, expr, label, err)
+			}
+			if policy.minForLabelSelector == nil {
+				policy.minForLabelSelector = make(map[string]*regexp.Regexp)
+			}
+			policy.minForLabelSelector[label] = re
+		}
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
+
+func (p ruleLintPolicy) appliesTo(namespace string) bool {
+	if len(p.namespaces) == 0 {
+		return true
+	}
+	for _, re := range p.namespaces {
+		if re.MatchString(namespace) {
+			return true
+		}
+	}
+	return false
+}
+
+// lintRuleGroups evaluates the policies matching namespace on every rule. A rule failing
+// validateRule or validateRuleTemplates is reported as an error finding and not linted further.
+func lintRuleGroups(namespace string, ruleGroups RuleGroups, policies []ruleLintPolicy) []ruleLintFinding {
+	var findings []ruleLintFinding
+
+	var applicable []ruleLintPolicy
+	for _, p := range policies {
+		if p.appliesTo(namespace) {
+			applicable = append(applicable, p)
+		}
+	}
+	if len(applicable) == 0 {
+		return nil
+	}
+
+	for i, group := range ruleGroups.Groups {
+		for j, rule := range group.Rules {
+			ruleName := rule.Alert
+			if ruleName == "" {
+				ruleName = rule.Record
+			}
+			err := validateRule(rule, i, j, group.Name)
+			if err == nil {
+				err = validateRuleTemplates(rule)
+			}
+			if err != nil {
+				findings = append(findings, ruleLintFinding{
+					Policy:   "validate",
+					Severity: ruleLintSeverityError,
+					Group:    group.Name,
+					Rule:     ruleName,
+					Message:  err.Error(),
+				})
+				continue
+			}
+			for _, p := range applicable {
+				for _, msg := range p.check(group, rule) {
+					findings = append(findings, ruleLintFinding{
+						Policy:   p.name,
+						Severity: p.severity,
+						Group:    group.Name,
+						Rule:     ruleName,
+						Message:  msg,
+					})
+				}
+			}
+		}
+	}
+	return findings
+}
+
+func (p ruleLintPolicy) check(group RuleGroup, rule Rule) []string {
+	var msgs []string
+
+	if rule.Record != "" {
+		if p.recordingRuleNameFormat && !validRecordingRuleNameFormat(rule.Record) {
+			msgs = append(msgs, fmt.Sprintf("recording rule name %q does not follow the 'level:metric:operations' convention", rule.Record))
+		}
+		return msgs
+	}
+
+	// Group-level labels are added to every rule of the group.
+	labels := make(map[string]string)
+	for k, v := range group.Labels {
+		labels[k] = v
+	}
+	for k, v := range rule.Labels {
+		labels[k] = v
+	}
+
+	for _, l := range p.requiredAlertLabels {
+		if _, ok := labels[l]; !ok {
+			msgs = append(msgs, fmt.Sprintf("missing required label %q", l))
+		}
+	}
+	for _, a := range p.requiredAlertAnnotations {
+		if _, ok := rule.Annotations[a]; !ok {
+			msgs = append(msgs, fmt.Sprintf("missing required annotation %q", a))
+		}
+	}
+
+	if p.minFor > 0 && p.matchesMinForSelector(labels) {
+		var forDuration time.Duration
+		if rule.For != "" {
+			// validateRule already rejected an unparsable duration
+			d, _ := model.ParseDuration(rule.For)
+			forDuration = time.Duration(d)
+		}
+		if forDuration < p.minFor {
+			msgs = append(msgs, fmt.Sprintf("'for' duration %s is shorter than the minimum %s", model.Duration(forDuration), model.Duration(p.minFor)))
+		}
+	}
+
+	return msgs
+}
+
+func (p ruleLintPolicy) matchesMinForSelector(labels map[string]string) bool {
+	for label, re := range p.minForLabelSelector {
+		if !re.MatchString(labels[label]) {
+			return false
+		}
+	}
+	return true
+}
+
+// validRecordingRuleNameFormat reports whether name is a valid metric name made of exactly
+// three non-empty 'level:metric:operations' parts.
+func validRecordingRuleNameFormat(name string) bool {
+	if !metricNameRegexp.MatchString(name) {
+		return false
+	}
+	parts := strings.Split(name, ":")
+	if len(parts) != 3 {
+		return false
+	}
+	for _, part := range parts {
+		if part == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// ruleLintFindings returns the findings of policies and of the enabled promql_checks.
+func ruleLintFindings(namespace string, ruleGroups RuleGroups, policies []ruleLintPolicy) []ruleLintFinding {
+	findings := lintRuleGroups(namespace, ruleGroups, policies)
+	return append(findings, promQLCheckRuleGroups(ruleGroups, enabledPromQLChecks)...)
+}
+
+// ruleLintCheck evaluates the provider rule_lint policies and promql_checks at plan time. Error findings fail
+// the plan; warning findings are logged here and reported again as diagnostics on apply
+// (CustomizeDiff cannot return warnings).
+func ruleLintCheck(ctx context.Context, namespace string, ruleGroups RuleGroups) error {
+	var errs []string
+	for _, f := range ruleLintFindings(namespace, ruleGroups, ruleLintPolicies) {
+		if f.Severity == ruleLintSeverityError {
+			errs = append(errs, f.String())
+		} else {
+			tflog.Warn(ctx, "Rule lint", map[string]interface{}{
+				"namespace": namespace,
+				"finding":   f.String(),
+			})
+		}
+	}
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return fmt.Errorf("rule lint failed (namespace: %s):\n  - %s", namespace, strings.Join(errs, "\n  - "))
+	}
+	return nil
+}
+
+// ruleLintWarnings returns the warning-level rule_lint and promql_checks findings as diagnostics.
+func ruleLintWarnings(namespace string, ruleGroups RuleGroups) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, f := range ruleLintFindings(namespace, ruleGroups, ruleLintPolicies) {
+		if f.Severity != ruleLintSeverityWarning {
+			continue
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Rule lint (namespace: %s): %s", namespace, f),
+		})
+	}
+	return diags
+}
+
+// alertingRuleGroupToRuleGroup converts the typed alerting group into the generic RuleGroup
+// shared by mimir_rules, so both are linted the same way.
+func alertingRuleGroupToRuleGroup(g alertingRuleGroup) RuleGroup {
+	group := RuleGroup{Name: g.Name, Interval: g.Interval, SourceTenants: g.SourceTenants, Labels: g.Labels}
+	for _, r := range g.Rules {
+		group.Rules = append(group.Rules, Rule{
+			Alert:       r.Alert,
+			Expr:        r.Expr,
+			For:         r.For,
+			Labels:      r.Labels,
+			Annotations: r.Annotations,
+		})
+	}
+	return group
+}
+
+// recordingRuleGroupToRuleGroup converts the typed recording group into the generic RuleGroup.
+func recordingRuleGroupToRuleGroup(g recordingRuleGroup) RuleGroup {
+	group := RuleGroup{Name: g.Name, Interval: g.Interval, SourceTenants: g.SourceTenants, Labels: g.Labels}
+	for _, r := range g.Rules {
+		group.Rules = append(group.Rules, Rule{
+			Record: r.Record,
+			Expr:   r.Expr,
+			Labels: r.Labels,
+		})
+	}
+	return group
+}
+
+// ruleGroupsKnown reports whether every rule has its required fields set. Fields computed
+// from other resources read as empty at plan time; linting them would report bogus
+// violations, so such groups are only linted on apply.
+func ruleGroupsKnown(ruleGroups RuleGroups) bool {
+	for _, group := range ruleGroups.Groups {
+		for _, rule := range group.Rules {
+			if rule.Expr == "" || (rule.Alert == "" && rule.Record == "") {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// alertingRuleGroupLintInput builds the rule groups linted for a mimir_rule_group_alerting
+// from the planned diff or the resource data (get is their Get method).
+func alertingRuleGroupLintInput(get func(string) interface{}) RuleGroups {
+	group := alertingRuleGroupToRuleGroup(alertingRuleGroup{
+		Name:     get("name").(string),
+		Interval: get(intervalKey).(string),
+		Labels:   expandStringMap(get(labelsKey).(map[string]interface{})),
+		Rules:    expandAlertingRules(get("rule").([]interface{})),
+	})
+	return RuleGroups{Groups: []RuleGroup{group}}
+}
+
+// recordingRuleGroupLintInput builds the rule groups linted for a mimir_rule_group_recording
+// from the planned diff or the resource data (get is their Get method).
+func recordingRuleGroupLintInput(get func(string) interface{}) RuleGroups {
+	group := recordingRuleGroupToRuleGroup(recordingRuleGroup{
+		Name:     get("name").(string),
+		Interval: get(intervalKey).(string),
+		Labels:   expandStringMap(get(labelsKey).(map[string]interface{})),
+		Rules:    expandRecordingRules(get("rule").([]interface{})),
+	})
+	return RuleGroups{Groups: []RuleGroup{group}}
+}
@@ -0,0 +1,152 @@
+package mimir
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const ruleLintTestContent = `groups:
+- name: alerts
+  labels:
+    team: a
+  rules:
+  - alert: Paging
+    expr: up == 0
+    for: 1m
+    labels:
+      severity: page
+  - alert: Ticket
+    expr: up == 0
+    labels:
+      severity: ticket
+    annotations:
+      runbook_url: http://runbooks/ticket
+- name: records
+  rules:
+  - record: job:up:sum
+    expr: sum by (job) (up)
+  - record: up_sum
+    expr: sum(up)
+`
+
+func ruleLintTestGroups(t *testing.T) RuleGroups {
+	t.Helper()
+	var ruleGroups RuleGroups
+	if err := yaml.Unmarshal([]byte(ruleLintTestContent), &ruleGroups); err != nil {
+		t.Fatal(err)
+	}
+	return ruleGroups
+}
+
+func TestExpandRuleLintPolicies(t *testing.T) {
+	policies, err := expandRuleLintPolicies([]interface{}{
+		map[string]interface{}{
+			"name":                       "",
+			"severity":                   "warning",
+			"namespaces":                 []interface{}{"team-.*"},
+			"required_alert_labels":      []interface{}{"severity"},
+			"required_alert_annotations": []interface{}{},
+			"recording_rule_name_format": true,
+			"min_for":                    "5m",
+			"min_for_label_selector":     map[string]interface{}{"severity": "page"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(policies) != 1 {
+		t.Fatalf("expected 1 policy, got %d", len(policies))
+	}
+	p := policies[0]
+	if p.name != "rule_lint[0]" {
+		t.Errorf("unexpected default name %q", p.name)
+	}
+	if !p.appliesTo("team-a") || p.appliesTo("other-team-a") {
+		t.Error("namespace regex must be fully anchored")
+	}
+
+	_, err = expandRuleLintPolicies([]interface{}{
+		map[string]interface{}{
+			"name":                       "bad",
+			"severity":                   "error",
+			"namespaces":                 []interface{}{},
+			"required_alert_labels":      []interface{}{},
+			"required_alert_annotations": []interface{}{},
+			"recording_rule_name_format": false,
+			"min_for":                    "",
+			"min_for_label_selector":     map[string]interface{}{"severity": "("},
+		},
+	})
+	if err == nil {
+		t.Error("expected an error for an invalid min_for_label_selector regex")
+	}
+}
+
+func TestLintRuleGroups(t *testing.T) {
+	policies := []ruleLintPolicy{
+		{
+			name:                     "alerts",
+			severity:                 ruleLintSeverityError,
+			requiredAlertLabels:      []string{"severity", "team"},
+			requiredAlertAnnotations: []string{"runbook_url"},
+		},
+		{
+			name:                    "records",
+			severity:                ruleLintSeverityWarning,
+			recordingRuleNameFormat: true,
+		},
+	}
+	minFor, err := expandRuleLintPolicies([]interface{}{
+		map[string]interface{}{
+			"name":                       "paging",
+			"severity":                   "error",
+			"namespaces":                 []interface{}{},
+			"required_alert_labels":      []interface{}{},
+			"required_alert_annotations": []interface{}{},
+			"recording_rule_name_format": false,
+			"min_for":                    "5m",
+			"min_for_label_selector":     map[string]interface{}{"severity": "page|critical"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	policies = append(policies, minFor...)
+
+	var got []string
+	for _, f := range lintRuleGroups("default", ruleLintTestGroups(t), policies) {
+		got = append(got, f.Severity+" "+f.String())
+	}
+	want := []string{
+		`error [alerts] group 'alerts', rule 'Paging': missing required annotation "runbook_url"`,
+		`error [paging] group 'alerts', rule 'Paging': 'for' duration 1m is shorter than the minimum 5m`,
+		`warning [records] group 'records', rule 'up_sum': recording rule name "up_sum" does not follow the 'level:metric:operations' convention`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected findings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	policies[0].namespaces = []*regexp.Regexp{regexp.MustCompile("^(?:prod)$")}
+	for _, f := range lintRuleGroups("default", ruleLintTestGroups(t), policies[:1]) {
+		t.Errorf("policy restricted to another namespace reported %s", f)
+	}
+}
+
+func TestValidRecordingRuleNameFormat(t *testing.T) {
+	for name, want := range map[string]bool{
+		"job:up:sum":                    true,
+		"instance_path:requests:rate5m": true,
+		"up_sum":                        false,
+		"job:up":                        false,
+		"job::sum":                      false,
+		"a:b:c:d":                       false,
+		"job:up-down:sum":               false,
+	} {
+		if got := validRecordingRuleNameFormat(name); got != want {
+			t.Errorf("validRecordingRuleNameFormat(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
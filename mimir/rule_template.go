@@ -0,0 +1,70 @@
+package mimir
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// ruleTemplateDefs are the variables the ruler defines before expanding alert templates.
+var ruleTemplateDefs = []string{
+	"{{$labels := .Labels}}",
+	"{{$externalLabels := .ExternalLabels}}",
+	"{{$externalURL := .ExternalURL}}",
+	"{{$value := .Value}}",
+}
+
+// validateRuleTemplates parses the label and annotation values of an alerting rule with
+// the ruler template engine, rejecting syntax errors and unknown functions. References to
+// labels the expression cannot produce are reported by the aggregation_drops_labels check.
+func validateRuleTemplates(rule Rule) error {
+	if rule.Alert == "" {
+		return nil
+	}
+
+	for _, key := range sortedKeys(rule.Labels) {
+		if err := parseRuleTemplate(rule.Alert, rule.Labels[key]); err != nil {
+			return fmt.Errorf("label %q: invalid template: %v", key, err)
+		}
+	}
+	for _, key := range sortedKeys(rule.Annotations) {
+		if err := parseRuleTemplate(rule.Alert, rule.Annotations[key]); err != nil {
+			return fmt.Errorf("annotation %q: invalid template: %v", key, err)
+		}
+	}
+	return nil
+}
+
+// ruleTemplateFuncs are the functions of the ruler template engine (prometheus/template).
+// Parsing only needs their names; importing the engine would pull the whole PromQL engine.
+var ruleTemplateFuncs = func() template.FuncMap {
+	funcs := make(template.FuncMap)
+	for _, name := range []string{
+		"query", "first", "label", "value", "strvalue", "args", "reReplaceAll", "safeHtml",
+		"match", "title", "toUpper", "toLower", "graphLink", "tableLink", "sortByLabel",
+		"stripPort", "stripDomain", "humanize", "humanize1024", "humanizeDuration",
+		"humanizePercentage", "humanizeTimestamp", "toTime", "toDuration", "now",
+		"pathPrefix", "externalURL", "parseDuration", "urlQueryEscape",
+	} {
+		funcs[name] = func(...interface{}) interface{} { return nil }
+	}
+	return funcs
+}()
+
+func parseRuleTemplate(name, text string) error {
+	_, err := template.New("__alert_" + name).
+		Funcs(ruleTemplateFuncs).
+		Option("missingkey=zero").
+		Parse(strings.Join(ruleTemplateDefs, "") + text)
+	return err
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,110 @@
+package mimir
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+func TestValidateRuleTemplates(t *testing.T) {
+	cases := []struct {
+		name    string
+		rule    Rule
+		wantErr string
+	}{
+		{
+			name: "valid",
+			rule: Rule{
+				Alert:       "A",
+				Expr:        `up == 0`,
+				Labels:      map[string]string{"severity": "page", "instance_copy": "{{ $labels.instance }}"},
+				Annotations: map[string]string{"summary": `{{ $labels.instance }} {{ $value | humanize }} {{ $labels.severity }} {{ $labels.team }}`},
+			},
+		},
+		{
+			name:    "recording rules are not checked",
+			rule:    Rule{Record: "job:up:sum", Expr: `sum by (job) (up)`},
+			wantErr: "",
+		},
+		{
+			name:    "syntax error",
+			rule:    Rule{Alert: "A", Expr: `up == 0`, Annotations: map[string]string{"summary": `{{ $labels.instance }`}},
+			wantErr: `annotation "summary": invalid template`,
+		},
+		{
+			name:    "unknown function",
+			rule:    Rule{Alert: "A", Expr: `up == 0`, Annotations: map[string]string{"summary": `{{ $value | humanise }}`}},
+			wantErr: `function "humanise" not defined`,
+		},
+		{
+			name:    "label syntax error",
+			rule:    Rule{Alert: "A", Expr: `up == 0`, Labels: map[string]string{"copy": "{{ $labels.job "}},
+			wantErr: `label "copy": invalid template`,
+		},
+		{
+			name: "labels dropped by the expression are left to aggregation_drops_labels",
+			rule: Rule{Alert: "A", Expr: `sum by (job) (up) == 0`, Annotations: map[string]string{"summary": `{{ $labels.instance }}`}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := validateRuleTemplates(c.rule)
+			switch {
+			case c.wantErr == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case c.wantErr != "" && err == nil:
+				t.Errorf("expected an error containing %q", c.wantErr)
+			case c.wantErr != "" && !strings.Contains(err.Error(), c.wantErr):
+				t.Errorf("error %q does not contain %q", err, c.wantErr)
+			}
+		})
+	}
+}
+
+func TestPromQLDropsLabel(t *testing.T) {
+	cases := []struct {
+		expr  string
+		label string
+		want  bool
+	}{
+		{`sum by (job) (up) == 0`, "instance", true},
+		{`sum by (job) (up) == 0`, "job", false},
+		{`count without (instance) (up) == 0`, "instance", true},
+		{`absent(up{job="api"})`, "job", false},
+		{`absent(up{job=~"api"})`, "job", true},
+		{`up * on (instance) group_left (version) build_info`, "version", false},
+		{`up * on (instance) group_left (version) build_info`, "job", false},
+	}
+	for _, c := range cases {
+		expr, err := parser.NewParser(parser.Options{}).ParseExpr(c.expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := promQLDropsLabel(expr, c.label); got != c.want {
+			t.Errorf("promQLDropsLabel(%s, %s) = %v, want %v", c.expr, c.label, got, c.want)
+		}
+	}
+}
+
+func TestValidateRuleGroupsContent_Templates(t *testing.T) {
+	ruleGroups := RuleGroups{Groups: []RuleGroup{{
+		Name: "g",
+		Rules: []Rule{{
+			Alert:       "A",
+			Expr:        `sum(up) == 0`,
+			Annotations: map[string]string{"summary": `{{ $value | humanise }}`},
+		}},
+	}}}
+	err := validateRuleGroupsContent(ruleGroups)
+	if err == nil || !strings.Contains(err.Error(), `group 0 (g), rule 0: annotation "summary": invalid template`) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// A label dropped by the expression is a toggleable warning, not an error
+	ruleGroups.Groups[0].Rules[0].Annotations["summary"] = `{{ $labels.instance }}`
+	if err := validateRuleGroupsContent(ruleGroups); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
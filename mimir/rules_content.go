@@ -0,0 +1,262 @@
+package mimir
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ruleContentSources are the mutually exclusive attributes of mimir_rules holding its
+// rule groups.
+var ruleContentSources = []string{"content", "content_file", "content_files", "content_dir"}
+
+const (
+	ruleContentFormatRules          = "rules"
+	ruleContentFormatPrometheusRule = "prometheusrule"
+)
+
+// prometheusRule is the part of a Prometheus Operator PrometheusRule manifest holding its
+// rule groups.
+type prometheusRule struct {
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+	Metadata   struct {
+		Name      string `yaml:"name"`
+		Namespace string `yaml:"namespace"`
+	} `yaml:"metadata"`
+	Spec struct {
+		Groups []RuleGroup `yaml:"groups"`
+	} `yaml:"spec"`
+}
+
+// ruleContentOptions tells how to read the rule groups of a content source.
+type ruleContentOptions struct {
+	format string
+	// namespaceFrom is the PrometheusRule metadata field mapped to the Mimir namespace:
+	// only the groups of the manifests whose field equals namespace are kept.
+	namespaceFrom string
+	namespace     string
+}
+
+func ruleContentOptionsFrom(get func(string) interface{}) ruleContentOptions {
+	opts := ruleContentOptions{format: ruleContentFormatRules}
+	if format, _ := get("content_format").(string); format != "" {
+		opts.format = format
+	}
+	opts.namespaceFrom, _ = get("namespace_from").(string)
+	opts.namespace, _ = get(namespaceKey).(string)
+	return opts
+}
+
+// yamlDocuments returns the non-empty documents of a multi-document YAML stream.
+func yamlDocuments(data []byte) ([]*yaml.Node, error) {
+	var docs []*yaml.Node
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc yaml.Node
+		err := decoder.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return docs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(doc.Content) > 0 && doc.Content[0].Tag != "!!null" {
+			docs = append(docs, &doc)
+		}
+	}
+}
+
+// documentKind returns the kind of a Kubernetes manifest, empty for other documents.
+func documentKind(doc *yaml.Node) string {
+	var manifest struct {
+		Kind string `yaml:"kind"`
+	}
+	_ = doc.Decode(&manifest)
+	return manifest.Kind
+}
+
+// parsePrometheusRules decodes the PrometheusRule manifests of data.
+func parsePrometheusRules(data []byte) ([]prometheusRule, error) {
+	docs, err := yamlDocuments(data)
+	if err != nil {
+		return nil, err
+	}
+	var rules []prometheusRule
+	for i, doc := range docs {
+		var rule prometheusRule
+		if err := doc.Decode(&rule); err != nil {
+			return nil, fmt.Errorf("document %d: %w", i+1, err)
+		}
+		if rule.Kind != "PrometheusRule" {
+			return nil, fmt.Errorf("document %d: expected a PrometheusRule, got kind %q", i+1, rule.Kind)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// parseRuleGroupsContent decodes the rule groups of data, a (multi-document) rule groups
+// file or a stream of PrometheusRule manifests depending on the format.
+func parseRuleGroupsContent(data []byte, opts ruleContentOptions) (RuleGroups, error) {
+	var ruleGroups RuleGroups
+	if opts.format == ruleContentFormatPrometheusRule {
+		rules, err := parsePrometheusRules(data)
+		if err != nil {
+			return ruleGroups, err
+		}
+		for _, rule := range rules {
+			if opts.namespaceFrom != "" && prometheusRuleNamespace(rule, opts.namespaceFrom) != opts.namespace {
+				continue
+			}
+			ruleGroups.Groups = append(ruleGroups.Groups, rule.Spec.Groups...)
+		}
+		return ruleGroups, nil
+	}
+
+	docs, err := yamlDocuments(data)
+	if err != nil {
+		return ruleGroups, err
+	}
+	for _, doc := range docs {
+		if documentKind(doc) == "PrometheusRule" {
+			return ruleGroups, fmt.Errorf("found a PrometheusRule manifest, set content_format = %q to read it", ruleContentFormatPrometheusRule)
+		}
+		var docGroups RuleGroups
+		if err := doc.Decode(&docGroups); err != nil {
+			return ruleGroups, err
+		}
+		ruleGroups.Groups = append(ruleGroups.Groups, docGroups.Groups...)
+	}
+	return ruleGroups, nil
+}
+
+// prometheusRuleNamespace returns the metadata field of rule mapped to the Mimir namespace.
+func prometheusRuleNamespace(rule prometheusRule, namespaceFrom string) string {
+	if namespaceFrom == "metadata.name" {
+		return rule.Metadata.Name
+	}
+	return rule.Metadata.Namespace
+}
+
+// duplicateRuleGroupError reports a rule group defined twice across the content files.
+type duplicateRuleGroupError struct {
+	name, first, second string
+}
+
+func (e *duplicateRuleGroupError) Error() string {
+	if e.first == e.second {
+		return fmt.Sprintf("rule group '%s' is defined twice in %s", e.name, e.first)
+	}
+	return fmt.Sprintf("rule group '%s' is defined in both %s and %s", e.name, e.first, e.second)
+}
+
+// readRuleGroupsSources reads the rule groups of the content source set, through get
+// (d.Get or diff.Get). Groups read from files are merged in file order and record the
+// file they come from.
+func readRuleGroupsSources(get func(string) interface{}) (RuleGroups, error) {
+	var ruleGroups RuleGroups
+	opts := ruleContentOptionsFrom(get)
+	if content := get("content").(string); content != "" {
+		contentGroups, err := parseRuleGroupsContent([]byte(content), opts)
+		if err != nil {
+			return ruleGroups, fmt.Errorf("failed to parse YAML content: %w", err)
+		}
+		return contentGroups, checkMappedRuleGroups(contentGroups, opts)
+	}
+
+	files, err := ruleGroupsFiles(get)
+	if err != nil {
+		return ruleGroups, err
+	}
+	if len(files) == 0 {
+		return ruleGroups, fmt.Errorf("no rule configuration provided")
+	}
+
+	sources := make(map[string]string)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return ruleGroups, fmt.Errorf("failed to read file %s: %w", file, err)
+		}
+		fileGroups, err := parseRuleGroupsContent(data, opts)
+		if err != nil {
+			return ruleGroups, fmt.Errorf("failed to parse YAML file %s: %w", file, err)
+		}
+		for _, group := range fileGroups.Groups {
+			if first, ok := sources[group.Name]; ok {
+				return ruleGroups, &duplicateRuleGroupError{name: group.Name, first: first, second: file}
+			}
+			sources[group.Name] = file
+			group.file = file
+			ruleGroups.Groups = append(ruleGroups.Groups, group)
+		}
+	}
+	return ruleGroups, checkMappedRuleGroups(ruleGroups, opts)
+}
+
+// checkMappedRuleGroups reports when no PrometheusRule maps to the namespace, which would
+// otherwise only fail as an empty content.
+func checkMappedRuleGroups(ruleGroups RuleGroups, opts ruleContentOptions) error {
+	if opts.namespaceFrom != "" && len(ruleGroups.Groups) == 0 {
+		return fmt.Errorf("no PrometheusRule has %s %q", opts.namespaceFrom, opts.namespace)
+	}
+	return nil
+}
+
+// ruleGroupsFiles returns the files of content_file, content_files or content_dir, in
+// order and without duplicates. A content_files entry may be a glob pattern, which must
+// match at least one file; content_dir holds the .yaml and .yml files of the directory.
+func ruleGroupsFiles(get func(string) interface{}) ([]string, error) {
+	var files []string
+	add := func(paths ...string) {
+		for _, path := range paths {
+			if !contains(files, path) {
+				files = append(files, path)
+			}
+		}
+	}
+
+	if file := get("content_file").(string); file != "" {
+		add(file)
+	}
+	for _, raw := range get("content_files").([]interface{}) {
+		pattern, _ := raw.(string)
+		if !strings.ContainsAny(pattern, "*?[") {
+			add(pattern)
+			continue
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid content_files pattern %q: %w", pattern, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("content_files pattern %q matches no file", pattern)
+		}
+		sort.Strings(matches)
+		add(matches...)
+	}
+	if dir := get("content_dir").(string); dir != "" {
+		var matches []string
+		for _, ext := range []string{"*.yaml", "*.yml"} {
+			m, err := filepath.Glob(filepath.Join(dir, ext))
+			if err != nil {
+				return nil, fmt.Errorf("invalid content_dir %q: %w", dir, err)
+			}
+			matches = append(matches, m...)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("content_dir %q holds no .yaml or .yml file", dir)
+		}
+		sort.Strings(matches)
+		add(matches...)
+	}
+	return files, nil
+}
@@ -0,0 +1,233 @@
+package mimir
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func writeRulesFile(t *testing.T, dir, name string, groups ...string) string {
+	t.Helper()
+	var b strings.Builder
+	b.WriteString("groups:\n")
+	for _, group := range groups {
+		b.WriteString("  - name: " + group + "\n    rules:\n      - record: " + group + ":up\n        expr: up\n")
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(b.String()), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadRuleGroupsSources(t *testing.T) {
+	dir := t.TempDir()
+	alerts := writeRulesFile(t, dir, "alerts.yaml", "g1", "g2")
+	rules := writeRulesFile(t, dir, "rules.yml", "g3")
+	writeRulesFile(t, dir, "README.md", "ignored")
+
+	s := resourceMimirRules().Schema
+	tests := []struct {
+		name  string
+		raw   map[string]interface{}
+		files []string
+	}{
+		{
+			name:  "content_dir",
+			raw:   map[string]interface{}{"content_dir": dir},
+			files: []string{alerts, alerts, rules},
+		},
+		{
+			name:  "content_files with a glob pattern",
+			raw:   map[string]interface{}{"content_files": []interface{}{rules, filepath.Join(dir, "*.y*ml")}},
+			files: []string{rules, alerts, alerts},
+		},
+		{
+			name:  "content_file",
+			raw:   map[string]interface{}{"content_file": rules},
+			files: []string{rules},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, s, tt.raw)
+			ruleGroups, err := parseRuleGroupsConfiguration(d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var files []string
+			for _, group := range ruleGroups.Groups {
+				files = append(files, group.file)
+			}
+			if strings.Join(files, ",") != strings.Join(tt.files, ",") {
+				t.Errorf("got files %v, want %v", files, tt.files)
+			}
+
+			setComputedFields(d, ruleGroups, []string{"g3"}, map[string]string{})
+			if got := d.Get("groups.0.file"); got != rules {
+				t.Errorf("expected the file of g3 in groups, got %v", got)
+			}
+		})
+	}
+}
+
+func TestReadRuleGroupsSourcesErrors(t *testing.T) {
+	dir := t.TempDir()
+	first := writeRulesFile(t, dir, "team-a.yaml", "shared", "a")
+	second := writeRulesFile(t, dir, "team-b.yaml", "b", "shared")
+	twice := writeRulesFile(t, t.TempDir(), "twice.yaml", "x", "x")
+
+	s := resourceMimirRules().Schema
+	tests := []struct {
+		name string
+		raw  map[string]interface{}
+		want string
+	}{
+		{
+			name: "duplicate across files",
+			raw:  map[string]interface{}{"content_dir": dir},
+			want: "rule group 'shared' is defined in both " + first + " and " + second,
+		},
+		{
+			name: "duplicate in a file",
+			raw:  map[string]interface{}{"content_files": []interface{}{twice}},
+			want: "rule group 'x' is defined twice in " + twice,
+		},
+		{
+			name: "pattern without match",
+			raw:  map[string]interface{}{"content_files": []interface{}{filepath.Join(dir, "*.json")}},
+			want: "matches no file",
+		},
+		{
+			name: "empty directory",
+			raw:  map[string]interface{}{"content_dir": t.TempDir()},
+			want: "holds no .yaml or .yml file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseRuleGroupsConfiguration(schema.TestResourceDataRaw(t, s, tt.raw))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+const prometheusRuleManifests = `apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: api
+  namespace: team-a
+spec:
+  groups:
+    - name: api.alerts
+      rules:
+        - alert: APIDown
+          expr: up{job="api"} == 0
+          for: 5m
+---
+# An empty document is skipped
+---
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: billing
+  namespace: team-b
+spec:
+  groups:
+    - name: billing.rules
+      rules:
+        - record: job:up:sum
+          expr: sum by (job) (up)
+`
+
+func TestPrometheusRuleContent(t *testing.T) {
+	s := resourceMimirRules().Schema
+	groupNames := func(ruleGroups RuleGroups) string {
+		var names []string
+		for _, group := range ruleGroups.Groups {
+			names = append(names, group.Name)
+		}
+		return strings.Join(names, ",")
+	}
+
+	tests := []struct {
+		name string
+		raw  map[string]interface{}
+		want string
+	}{
+		{
+			name: "every manifest",
+			raw:  map[string]interface{}{namespaceKey: "mixins", "content_format": "prometheusrule"},
+			want: "api.alerts,billing.rules",
+		},
+		{
+			name: "mapped from metadata.namespace",
+			raw:  map[string]interface{}{namespaceKey: "team-b", "content_format": "prometheusrule", "namespace_from": "metadata.namespace"},
+			want: "billing.rules",
+		},
+		{
+			name: "mapped from metadata.name",
+			raw:  map[string]interface{}{namespaceKey: "api", "content_format": "prometheusrule", "namespace_from": "metadata.name"},
+			want: "api.alerts",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.raw["content"] = prometheusRuleManifests
+			ruleGroups, err := parseRuleGroupsConfiguration(schema.TestResourceDataRaw(t, s, tt.raw))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := groupNames(ruleGroups); got != tt.want {
+				t.Errorf("got groups %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	_, err := parseRuleGroupsConfiguration(schema.TestResourceDataRaw(t, s, map[string]interface{}{
+		namespaceKey: "team-c", "content": prometheusRuleManifests, "content_format": "prometheusrule", "namespace_from": "metadata.namespace",
+	}))
+	if err == nil || !strings.Contains(err.Error(), `no PrometheusRule has metadata.namespace "team-c"`) {
+		t.Errorf("expected an error for an unmapped namespace, got %v", err)
+	}
+
+	_, err = parseRuleGroupsConfiguration(schema.TestResourceDataRaw(t, s, map[string]interface{}{
+		namespaceKey: "mixins", "content": prometheusRuleManifests,
+	}))
+	if err == nil || !strings.Contains(err.Error(), `set content_format = "prometheusrule"`) {
+		t.Errorf("expected a hint to set content_format, got %v", err)
+	}
+
+	_, err = parseRuleGroupsContent([]byte("apiVersion: v1\nkind: ConfigMap\n"), ruleContentOptions{format: ruleContentFormatPrometheusRule})
+	if err == nil || !strings.Contains(err.Error(), `expected a PrometheusRule, got kind "ConfigMap"`) {
+		t.Errorf("expected an error for another kind, got %v", err)
+	}
+}
+
+func TestValidateYAMLContentPrometheusRule(t *testing.T) {
+	if _, errs := validateYAMLContent(prometheusRuleManifests, "content"); len(errs) != 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+
+	invalid := strings.Replace(prometheusRuleManifests, "record: job:up:sum", "record: job-up-sum", 1)
+	_, errs := validateYAMLContent(invalid, "content")
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "PrometheusRule 'billing'") {
+		t.Errorf("expected the invalid record name of billing to be reported, got %v", errs)
+	}
+}
+
+func TestMultiDocumentRuleGroups(t *testing.T) {
+	content := "groups:\n  - name: a\n    rules:\n      - record: a\n        expr: up\n---\ngroups:\n  - name: b\n    rules:\n      - record: b\n        expr: up\n"
+	ruleGroups, err := parseRuleGroupsContent([]byte(content), ruleContentOptions{format: ruleContentFormatRules})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ruleGroups.Groups) != 2 || ruleGroups.Groups[1].Name != "b" {
+		t.Errorf("expected the groups of both documents, got %+v", ruleGroups.Groups)
+	}
+}
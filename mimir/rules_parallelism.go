@@ -0,0 +1,101 @@
+package mimir
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceRulesParallelism returns the number of rule groups a mimir_rules resource writes
+// concurrently: its own parallelism when set, the provider one otherwise.
+func resourceRulesParallelism(d *schema.ResourceData, meta interface{}) int {
+	if v := d.Get("parallelism").(int); v > 0 {
+		return v
+	}
+	if client, ok := meta.(*apiClient); ok && client.rulesParallelism > 0 {
+		return client.rulesParallelism
+	}
+	return 1
+}
+
+// forEachRuleGroup calls fn for each of names with up to parallelism calls in flight, and
+// returns the error of every name for which fn failed. Once ctx is done, the names not
+// started yet fail with the context error instead.
+func forEachRuleGroup(ctx context.Context, parallelism int, names []string, fn func(ctx context.Context, name string) error) map[string]error {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		errs = make(map[string]error)
+		sem  = make(chan struct{}, parallelism)
+	)
+	for _, name := range names {
+		select {
+		case <-ctx.Done():
+		case sem <- struct{}{}:
+		}
+		if err := ctx.Err(); err != nil {
+			mu.Lock()
+			errs[name] = err
+			mu.Unlock()
+			continue
+		}
+
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := fn(ctx, name); err != nil {
+				mu.Lock()
+				errs[name] = err
+				mu.Unlock()
+			}
+		}(name)
+	}
+	wg.Wait()
+	return errs
+}
+
+// ruleGroupErrorDiags returns one error diagnostic per failed rule group, in the order of
+// names, so a failure does not hide the others.
+func ruleGroupErrorDiags(action string, names []string, errs map[string]error) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, name := range names {
+		if err, ok := errs[name]; ok {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("failed to %s rule group '%s'", action, name),
+				Detail:   err.Error(),
+			})
+		}
+	}
+	return diags
+}
+
+// succeededRuleGroups returns the names for which no error was recorded, in order.
+func succeededRuleGroups(names []string, errs map[string]error) []string {
+	var succeeded []string
+	for _, name := range names {
+		if _, failed := errs[name]; !failed {
+			succeeded = append(succeeded, name)
+		}
+	}
+	return succeeded
+}
+
+// ruleGroupsByName returns the names of groups, in order, and the groups keyed by name.
+func ruleGroupsByName(groups RuleGroups) ([]string, map[string]RuleGroup) {
+	names := make([]string, 0, len(groups.Groups))
+	byName := make(map[string]RuleGroup, len(groups.Groups))
+	for _, group := range groups.Groups {
+		names = append(names, group.Name)
+		byName[group.Name] = group
+	}
+	return names, byName
+}
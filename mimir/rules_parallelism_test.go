@@ -0,0 +1,300 @@
+package mimir
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"gopkg.in/yaml.v3"
+)
+
+func TestForEachRuleGroup(t *testing.T) {
+	var inFlight, maxInFlight int32
+	names := []string{"a", "b", "c", "d", "e", "f"}
+	errs := forEachRuleGroup(context.Background(), 2, names, func(ctx context.Context, name string) error {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		if name == "c" || name == "e" {
+			return errors.New("boom")
+		}
+		return nil
+	})
+	if maxInFlight != 2 {
+		t.Errorf("expected 2 calls in flight at most, got %d", maxInFlight)
+	}
+	if len(errs) != 2 || errs["c"] == nil || errs["e"] == nil {
+		t.Errorf("unexpected errors %v", errs)
+	}
+	if got := succeededRuleGroups(names, errs); strings.Join(got, ",") != "a,b,d,f" {
+		t.Errorf("unexpected succeeded groups %v", got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	errs = forEachRuleGroup(ctx, 2, names, func(ctx context.Context, name string) error {
+		t.Errorf("unexpected call for %s after cancellation", name)
+		return nil
+	})
+	if len(errs) != len(names) {
+		t.Errorf("expected every group to fail with the context error, got %v", errs)
+	}
+}
+
+func TestResourceRulesParallelism(t *testing.T) {
+	cases := []struct {
+		resource int
+		provider int
+		want     int
+	}{
+		{want: 1},
+		{provider: 4, want: 4},
+		{resource: 2, provider: 4, want: 2},
+	}
+	for _, c := range cases {
+		d := schema.TestResourceDataRaw(t, resourceMimirRules().Schema, map[string]interface{}{"parallelism": c.resource})
+		if got := resourceRulesParallelism(d, &apiClient{rulesParallelism: c.provider}); got != c.want {
+			t.Errorf("resourceRulesParallelism(resource=%d, provider=%d) = %d, want %d", c.resource, c.provider, got, c.want)
+		}
+	}
+}
+
+// rulerTestServer is a ruler storing the rule groups of namespace "default".
+type rulerTestServer struct {
+	mu     sync.Mutex
+	groups map[string]string
+	writes []string
+	// fail reports whether to fail a request, given its method, the rule group and the
+	// number of write requests received so far, this one included.
+	fail func(method, name string, n int) bool
+}
+
+func newRulerTestServer(t *testing.T, fail func(method, name string, n int) bool) (*apiClient, *rulerTestServer) {
+	t.Helper()
+	ruler := &rulerTestServer{groups: make(map[string]string), fail: fail}
+	// The ruler API is served under the /prometheus prefix of the ruler url.
+	client := newTestAPIClient(t, http.StripPrefix("/prometheus", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ruler.mu.Lock()
+		defer ruler.mu.Unlock()
+		name := strings.TrimPrefix(r.URL.Path, rulesNamespacePath("default")+"/")
+		switch {
+		case r.Method == "POST" && r.URL.Path == rulesNamespacePath("default"):
+			body, _ := io.ReadAll(r.Body)
+			var group RuleGroup
+			if err := yaml.Unmarshal(body, &group); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			ruler.writes = append(ruler.writes, group.Name)
+			if ruler.fail(r.Method, group.Name, len(ruler.writes)) {
+				http.Error(w, "per-tenant rule groups limit exceeded", http.StatusBadRequest)
+				return
+			}
+			ruler.groups[group.Name] = string(body)
+			w.WriteHeader(http.StatusAccepted)
+		case r.Method == "DELETE" && name != r.URL.Path:
+			ruler.writes = append(ruler.writes, name)
+			if ruler.fail(r.Method, name, len(ruler.writes)) {
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+			delete(ruler.groups, name)
+			w.WriteHeader(http.StatusAccepted)
+		case r.Method == "GET" && name != r.URL.Path:
+			body, ok := ruler.groups[name]
+			if !ok {
+				http.Error(w, "group does not exist", http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(body))
+		default:
+			http.NotFound(w, r)
+		}
+	})))
+	return client, ruler
+}
+
+// stored returns the names of the stored rule groups, sorted.
+func (r *rulerTestServer) stored() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var names []string
+	for name := range r.groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// takeWrites returns the rule groups written or deleted since the last call.
+func (r *rulerTestServer) takeWrites() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	writes := r.writes
+	r.writes = nil
+	sort.Strings(writes)
+	return writes
+}
+
+const parallelRulesContent = `groups:
+  - name: g1
+    rules:
+      - record: a
+        expr: up
+  - name: g2
+    rules:
+      - record: b
+        expr: up
+  - name: g3
+    rules:
+      - record: c
+        expr: up
+  - name: g4
+    rules:
+      - record: d
+        expr: up
+`
+
+func TestResourceMimirRulesCreatePartialFailure(t *testing.T) {
+	client, ruler := newRulerTestServer(t, func(method, name string, n int) bool {
+		return name == "g2" || name == "g4"
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceMimirRules().Schema, map[string]interface{}{
+		namespaceKey:  "default",
+		"content":     parallelRulesContent,
+		"parallelism": 3,
+	})
+	diags := resourceMimirRulesCreate(context.Background(), d, client)
+	if len(diags) != 2 || !strings.Contains(diags[0].Summary, "'g2'") || !strings.Contains(diags[1].Summary, "'g4'") {
+		t.Fatalf("expected an error for g2 and g4, got %v", diags)
+	}
+	if !strings.Contains(diags[0].Detail, "limit exceeded") {
+		t.Errorf("expected the ruler error in the detail, got %q", diags[0].Detail)
+	}
+	if got := strings.Join(ruler.stored(), ","); got != "g1,g3" {
+		t.Errorf("expected g1 and g3 to be created, got %s", got)
+	}
+	if got := d.Get("managed_groups").([]interface{}); len(got) != 2 || got[0] != "g1" || got[1] != "g3" {
+		t.Errorf("expected the created groups to be recorded, got %v", got)
+	}
+	if d.Id() == "" {
+		t.Error("expected the resource ID to be set so the partial state is kept")
+	}
+}
+
+func TestResourceMimirRulesDeleteAggregatesErrors(t *testing.T) {
+	client, ruler := newRulerTestServer(t, func(method, name string, n int) bool {
+		return name == "g2" || name == "g3"
+	})
+	d := schema.TestResourceDataRaw(t, resourceMimirRules().Schema, map[string]interface{}{
+		namespaceKey: "default",
+		"content":    parallelRulesContent,
+	})
+	if diags := resourceMimirRulesCreate(context.Background(), d, client); len(diags) != 2 {
+		t.Fatalf("expected the creation of g2 and g3 to fail, got %v", diags)
+	}
+	d.Set("managed_groups", []string{"g1", "g2", "g3", "g4"})
+
+	diags := resourceMimirRulesDelete(context.Background(), d, client)
+	if len(diags) != 2 || !strings.Contains(diags[0].Summary, "'g2'") || !strings.Contains(diags[1].Summary, "'g3'") {
+		t.Fatalf("expected an error for g2 and g3, got %v", diags)
+	}
+	if got := ruler.stored(); len(got) != 0 {
+		t.Errorf("expected g1 and g4 to be deleted, got %v", got)
+	}
+	if got := d.Get("managed_groups").([]interface{}); len(got) != 2 || got[0] != "g2" || got[1] != "g3" {
+		t.Errorf("expected the groups left to be tracked, got %v", got)
+	}
+}
+
+// TestResourceMimirRulesResumesPartialApply runs plan and apply cycles against a ruler
+// failing the Nth write, and checks the next apply only writes the groups left.
+func TestResourceMimirRulesResumesPartialApply(t *testing.T) {
+	failAt := 3
+	client, ruler := newRulerTestServer(t, func(method, name string, n int) bool {
+		return n == failAt
+	})
+
+	ctx := context.Background()
+	r := resourceMimirRules()
+	apply := func(state *terraform.InstanceState, content string) (*terraform.InstanceState, diag.Diagnostics) {
+		t.Helper()
+		if state != nil {
+			var diags diag.Diagnostics
+			if state, diags = r.RefreshWithoutUpgrade(ctx, state, client); diags.HasError() {
+				t.Fatalf("refresh: %v", diags)
+			}
+		}
+		cfg := terraform.NewResourceConfigRaw(map[string]interface{}{namespaceKey: "default", "content": content})
+		diff, err := r.Diff(ctx, state, cfg, client)
+		if err != nil {
+			t.Fatalf("plan: %v", err)
+		}
+		if diff.Empty() {
+			return state, nil
+		}
+		return r.Apply(ctx, state, diff, client)
+	}
+
+	// The third write fails: g1, g2 and g4 are created and recorded.
+	state, diags := apply(nil, parallelRulesContent)
+	if len(diags) != 1 || !strings.Contains(diags[0].Summary, "'g3'") {
+		t.Fatalf("expected the creation of g3 to fail, got %v", diags)
+	}
+	if got := state.Attributes["managed_groups.#"]; got != "3" {
+		t.Fatalf("expected 3 groups to be recorded, got %s: %v", got, state.Attributes)
+	}
+	ruler.takeWrites()
+
+	// Once the resource is untainted, the next apply only creates g3.
+	if state, diags = apply(state, parallelRulesContent); diags.HasError() {
+		t.Fatalf("resume: %v", diags)
+	}
+	if got := strings.Join(ruler.takeWrites(), ","); got != "g3" {
+		t.Errorf("expected only g3 to be written, got %s", got)
+	}
+	if got := strings.Join(ruler.stored(), ","); got != "g1,g2,g3,g4" {
+		t.Errorf("expected every group to be stored, got %s", got)
+	}
+	if state, diags = apply(state, parallelRulesContent); diags.HasError() || len(ruler.takeWrites()) != 0 {
+		t.Fatalf("expected no change once every group is written, got %v", diags)
+	}
+
+	// Changing g2 and g4 fails on g2: g4 is updated, g2 keeps its previous content.
+	failAt = 1
+	changed := strings.Replace(strings.Replace(parallelRulesContent, "record: b\n        expr: up", "record: b\n        expr: up == 1", 1), "record: d\n        expr: up", "record: d\n        expr: up == 1", 1)
+	if state, diags = apply(state, changed); len(diags) != 1 || !strings.Contains(diags[0].Summary, "'g2'") {
+		t.Fatalf("expected the update of g2 to fail, got %v", diags)
+	}
+	if got := strings.Join(ruler.takeWrites(), ","); got != "g2,g4" {
+		t.Errorf("expected g2 and g4 to be written, got %s", got)
+	}
+
+	failAt = 0
+	if state, diags = apply(state, changed); diags.HasError() {
+		t.Fatalf("resume: %v", diags)
+	}
+	if got := strings.Join(ruler.takeWrites(), ","); got != "g2" {
+		t.Errorf("expected only g2 to be written again, got %s", got)
+	}
+	if _, diags = apply(state, changed); diags.HasError() || len(ruler.takeWrites()) != 0 {
+		t.Fatalf("expected no change once every group is written, got %v", diags)
+	}
+}
@@ -1752,6 +1752,8 @@ func resourceMimirAlertmanagerConfigSchemaV1() map[string]*schema.Schema {
 			Description: "A map of key values string, where the key is the template name and the value the content of the template.",
 			Elem:        &schema.Schema{Type: schema.TypeString},
 		},
+		"request_headers": requestHeadersSchema("resource"),
+		"auth":            requestAuthSchema("resource"),
 	}
 }
 
@@ -2188,5 +2190,7 @@ func dataSourceMimirAlertmanagerConfigSchemaV1() map[string]*schema.Schema {
 			Description: "A map of key values string, where the key is the template name and the value the content of the template.",
 			Elem:        &schema.Schema{Type: schema.TypeString},
 		},
+		"request_headers": requestHeadersSchema("data source"),
+		"auth":            requestAuthSchema("data source"),
 	}
 }
@@ -1,11 +1,13 @@
 package mimir
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
 	"unicode/utf8"
 
@@ -80,6 +82,12 @@ func rulesNamespacePath(namespace string) string {
 	return "/config/v1/rules/" + url.PathEscape(namespace)
 }
 
+// rulesListPath builds the ruler-API path that lists every namespace and rule group of a
+// tenant. It is the only ruler path with no escaped segment.
+func rulesListPath() string {
+	return "/config/v1/rules"
+}
+
 // buildRuleGroupID encodes the typed-resource Terraform ID with each segment URL-escaped,
 // so a delimiter-colliding character in a segment cannot shift the parse. An empty orgID
 // yields the 2-segment form.
@@ -139,6 +147,22 @@ func handleHTTPError(err error, baseMsg string) error {
 	return nil
 }
 
+// sleepContext waits for d, the delay before reading back a change, unless ctx is done first
+// (Ctrl-C or the resource timeout), in which case it returns the context error.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
 // Array to String Array
 func expandStringArray(v []interface{}) []string {
 	var m []string
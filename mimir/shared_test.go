@@ -1,14 +1,28 @@
 package mimir
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"gopkg.in/yaml.v3"
 )
@@ -51,7 +65,7 @@ func testAccCheckMimirRuleGroupHasLabel(n, group, key, val string, client *apiCl
 			headers["X-Scope-OrgID"] = orgID
 		}
 		path := rulesGroupPath(namespace, group)
-		body, err := client.sendRequest("ruler", "GET", path, "", headers)
+		body, err := client.sendRequest(context.Background(), "ruler", "GET", path, "", headers)
 		if err != nil {
 			return err
 		}
@@ -102,7 +116,7 @@ func testAccCheckMimirRuleGroupExists(n string, name string, client *apiClient)
 			headers["X-Scope-OrgID"] = orgID
 		}
 		path := rulesGroupPath(namespace, name)
-		_, err := client.sendRequest("ruler", "GET", path, "", headers)
+		_, err := client.sendRequest(context.Background(), "ruler", "GET", path, "", headers)
 		if err != nil {
 			return err
 		}
@@ -135,7 +149,7 @@ func testAccCheckMimirNamespaceExists(n string, name string, client *apiClient)
 			headers["X-Scope-OrgID"] = orgID
 		}
 		path := rulesNamespacePath(namespace)
-		_, err := client.sendRequest("ruler", "GET", path, "", headers)
+		_, err := client.sendRequest(context.Background(), "ruler", "GET", path, "", headers)
 		if err != nil {
 			return err
 		}
@@ -164,7 +178,7 @@ func testAccCheckMimirRuleGroupGone(n string, groupName string, client *apiClien
 			headers["X-Scope-OrgID"] = orgID
 		}
 		path := rulesGroupPath(namespace, groupName)
-		_, err := client.sendRequest("ruler", "GET", path, "", headers)
+		_, err := client.sendRequest(context.Background(), "ruler", "GET", path, "", headers)
 		if err == nil {
 			return fmt.Errorf("rule group %q in namespace %q still exists in Mimir; expected 404", groupName, namespace)
 		}
@@ -195,7 +209,7 @@ func testAccCheckMimirRuleGroupDestroy(s *terraform.State) error {
 			headers["X-Scope-OrgID"] = orgID
 		}
 		path := rulesGroupPath(namespace, name)
-		_, err := client.sendRequest("ruler", "GET", path, "", headers)
+		_, err := client.sendRequest(context.Background(), "ruler", "GET", path, "", headers)
 
 		// If the error is equivalent to 404 not found, the widget is destroyed.
 		// Otherwise return the error
@@ -233,7 +247,7 @@ func testAccCheckMimirRuleDestroy(s *terraform.State) error {
 			groupName := rs.Primary.Attributes[fmt.Sprintf("managed_groups.%d", i)]
 
 			path := rulesGroupPath(namespace, groupName)
-			_, err := client.sendRequest("ruler", "GET", path, "", headers)
+			_, err := client.sendRequest(context.Background(), "ruler", "GET", path, "", headers)
 
 			// If the error is equivalent to 404 not found, the group is destroyed.
 			// Otherwise return the error
@@ -267,3 +281,100 @@ func setupClient() *apiClientOpt {
 	}
 	return opt
 }
+
+// newTestServer starts a server serving handler, closed at the end of the test.
+func newTestServer(t *testing.T, handler http.Handler) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// newTestClient returns the client of opt, failing the test on errors.
+func newTestClient(t *testing.T, opt *apiClientOpt) *apiClient {
+	t.Helper()
+	client, err := NewAPIClient(opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+// echoHeadersHandler replies with the values of the named request headers, joined by "|".
+func echoHeadersHandler(names ...string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		values := make([]string, len(names))
+		for i, name := range names {
+			values[i] = r.Header.Get(name)
+		}
+		_, _ = w.Write([]byte(strings.Join(values, "|")))
+	})
+}
+
+// newTestAPIClient returns a client of a test server serving handler, laid out as a Mimir
+// behind a gateway: the ruler and query-frontend APIs under /prometheus and the
+// distributor under /distributor. Requests default to tenant-1.
+func newTestAPIClient(t *testing.T, handler http.Handler) *apiClient {
+	t.Helper()
+	srv := newTestServer(t, handler)
+	return newTestClient(t, &apiClientOpt{
+		uri:              srv.URL,
+		rulerURI:         srv.URL + "/prometheus",
+		queryFrontendURI: srv.URL + "/prometheus",
+		distributorURI:   srv.URL + "/distributor",
+		headers:          map[string]string{"X-Scope-OrgID": "tenant-1"},
+		timeout:          2,
+	})
+}
+
+// readTestDataSource reads the data source r configured with raw, failing on errors.
+func readTestDataSource(t *testing.T, r *schema.Resource, client *apiClient, raw map[string]interface{}) *schema.ResourceData {
+	t.Helper()
+	d := schema.TestResourceDataRaw(t, r.Schema, raw)
+	if diags := r.ReadContext(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+	return d
+}
+
+// newTestCert returns a self-signed certificate and its key in PEM, valid for 127.0.0.1.
+func newTestCert(t *testing.T, serial int64, notBefore, notAfter time.Time) ([]byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "terraform"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// newTestTLSServer starts a TLS server serving handler with its own self-signed
+// certificate, returned in PEM, closed at the end of the test.
+func newTestTLSServer(t *testing.T, handler http.Handler, serial int64) (*httptest.Server, []byte) {
+	t.Helper()
+	certPEM, keyPEM := newTestCert(t, serial, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewUnstartedServer(handler)
+	srv.TLS = &tls.Config{Certificates: []tls.Certificate{cert}}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	return srv, certPEM
+}
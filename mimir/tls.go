@@ -0,0 +1,312 @@
+package mimir
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// certExpiryWarningRatio is the part of its validity period left under which a client
+// certificate is reported as close to expiry.
+const certExpiryWarningRatio = 0.1
+
+func isInlinePEM(s string) bool {
+	return strings.HasPrefix(s, "-----BEGIN")
+}
+
+// fileStamp identifies a version of a file, to detect rotations without reading it.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+func stampFiles(paths []string) (map[string]fileStamp, error) {
+	stamps := make(map[string]fileStamp, len(paths))
+	for _, path := range paths {
+		// os.Stat follows symlinks, as used by Kubernetes secret mounts.
+		fi, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		stamps[path] = fileStamp{modTime: fi.ModTime(), size: fi.Size()}
+	}
+	return stamps, nil
+}
+
+func sameStamps(a, b map[string]fileStamp) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || !v.modTime.Equal(w.modTime) || v.size != w.size {
+			return false
+		}
+	}
+	return true
+}
+
+// certReloader serves the client certificate, re-reading the cert and key files when
+// they change so a rotated certificate is used by the next TLS handshakes.
+type certReloader struct {
+	certFile string
+	keyFile  string
+	debug    bool
+
+	mu     sync.Mutex
+	cert   *tls.Certificate
+	source string
+	stamps map[string]fileStamp
+	// logged is the certificate whose expiry was last logged.
+	logged *tls.Certificate
+}
+
+// newCertReloader loads the client certificate; cert and key are both either file paths or
+// inline PEM, the latter never being reloaded.
+func newCertReloader(cert, key string, debug bool) (*certReloader, error) {
+	r := &certReloader{debug: debug}
+	if isInlinePEM(cert) && isInlinePEM(key) {
+		c, err := tls.X509KeyPair([]byte(cert), []byte(key))
+		if err != nil {
+			return nil, err
+		}
+		if err := r.setCertificate(&c, "inline"); err != nil {
+			return nil, err
+		}
+		return r, nil
+	}
+
+	r.certFile, r.keyFile = cert, key
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetClientCertificate implements tls.Config.GetClientCertificate. A certificate that fails
+// to reload is reported and the previous one is kept, as the rotation may be in progress.
+// The expiry of a new certificate is logged on its first handshake, with the logger of the
+// request.
+func (r *certReloader) GetClientCertificate(info *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	ctx := context.Background()
+	if info != nil {
+		ctx = info.Context()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.certFile != "" {
+		if err := r.reload(); err != nil {
+			tflog.Warn(ctx, "Unable to reload the client certificate, keeping the previous one", map[string]interface{}{
+				"cert_file": r.certFile,
+				"error":     err.Error(),
+			})
+		}
+	}
+	if r.cert != r.logged {
+		logCertExpiry(ctx, r.cert.Leaf, r.source, time.Now(), r.debug)
+		r.logged = r.cert
+	}
+	return r.cert, nil
+}
+
+func (r *certReloader) reload() error {
+	stamps, err := stampFiles([]string{r.certFile, r.keyFile})
+	if err != nil {
+		return err
+	}
+	if r.cert != nil && sameStamps(stamps, r.stamps) {
+		return nil
+	}
+
+	c, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+	if err := r.setCertificate(&c, r.certFile); err != nil {
+		return err
+	}
+	r.stamps = stamps
+	return nil
+}
+
+func (r *certReloader) setCertificate(c *tls.Certificate, source string) error {
+	leaf, err := x509.ParseCertificate(c.Certificate[0])
+	if err != nil {
+		return fmt.Errorf("unable to parse the client certificate %s: %v", source, err)
+	}
+	c.Leaf = leaf
+	r.cert, r.source = c, source
+	return nil
+}
+
+// logCertExpiry logs the expiry of a client certificate in debug mode, and warns when less
+// than certExpiryWarningRatio of its validity period is left.
+func logCertExpiry(ctx context.Context, leaf *x509.Certificate, source string, now time.Time, debug bool) bool {
+	fields := map[string]interface{}{
+		"cert_file":  source,
+		"subject":    leaf.Subject.String(),
+		"expires_at": leaf.NotAfter.Format(time.RFC3339),
+	}
+	if debug {
+		tflog.Debug(ctx, "Client certificate loaded", fields)
+	}
+	lifetime := leaf.NotAfter.Sub(leaf.NotBefore)
+	remaining := leaf.NotAfter.Sub(now)
+	if remaining > time.Duration(float64(lifetime)*certExpiryWarningRatio) {
+		return false
+	}
+	if remaining <= 0 {
+		tflog.Warn(ctx, "Client certificate expired", fields)
+	} else {
+		fields["expires_in"] = remaining.Round(time.Second).String()
+		tflog.Warn(ctx, "Client certificate expires soon", fields)
+	}
+	return true
+}
+
+// caReloader builds the pool of trusted CAs from a file, a directory of files or inline PEM,
+// optionally on top of the system pool. Files are re-read when they change.
+type caReloader struct {
+	source        string
+	includeSystem bool
+
+	mu     sync.Mutex
+	pool   *x509.CertPool
+	stamps map[string]fileStamp
+}
+
+func newCAReloader(source string, includeSystem bool) (*caReloader, error) {
+	r := &caReloader{source: source, includeSystem: includeSystem}
+	if _, _, err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// reloadable reports whether the CAs are read from files.
+func (r *caReloader) reloadable() bool {
+	return r.source != "" && !isInlinePEM(r.source)
+}
+
+func (r *caReloader) files() ([]string, error) {
+	fi, err := os.Stat(r.source)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.IsDir() {
+		return []string{r.source}, nil
+	}
+
+	entries, err := os.ReadDir(r.source)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, e := range entries {
+		// Skip hidden entries, e.g. the ..data directory of Kubernetes secret mounts.
+		if strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(r.source, e.Name())
+		if fi, err := os.Stat(path); err == nil && fi.Mode().IsRegular() {
+			files = append(files, path)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// load returns the pool of trusted CAs and whether it changed since the previous call.
+func (r *caReloader) load() (*x509.CertPool, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var files []string
+	var stamps map[string]fileStamp
+	if r.reloadable() {
+		var err error
+		if files, err = r.files(); err != nil {
+			return r.pool, false, err
+		}
+		if stamps, err = stampFiles(files); err != nil {
+			return r.pool, false, err
+		}
+		if r.pool != nil && sameStamps(stamps, r.stamps) {
+			return r.pool, false, nil
+		}
+	} else if r.pool != nil {
+		return r.pool, false, nil
+	}
+
+	pool := x509.NewCertPool()
+	if r.includeSystem {
+		systemPool, err := x509.SystemCertPool()
+		if err != nil {
+			return r.pool, false, fmt.Errorf("unable to load the system CA pool: %v", err)
+		}
+		pool = systemPool
+	}
+	if r.source != "" && !r.reloadable() {
+		pool.AppendCertsFromPEM([]byte(r.source))
+	}
+	for _, path := range files {
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return r.pool, false, err
+		}
+		pool.AppendCertsFromPEM(pem)
+	}
+
+	r.pool, r.stamps = pool, stamps
+	return pool, true, nil
+}
+
+// caTransport is a http.RoundTripper using a transport trusting the CAs of a caReloader.
+// The transport is rebuilt when the CAs change, as the RootCAs of a transport in use cannot
+// be replaced.
+type caTransport struct {
+	ca           *caReloader
+	newTransport func(*x509.CertPool) *http.Transport
+
+	mu sync.Mutex
+	rt *http.Transport
+}
+
+func newCATransport(ca *caReloader, newTransport func(*x509.CertPool) *http.Transport) *caTransport {
+	pool, _, _ := ca.load()
+	return &caTransport{ca: ca, newTransport: newTransport, rt: newTransport(pool)}
+}
+
+func (t *caTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.transport(req.Context()).RoundTrip(req)
+}
+
+func (t *caTransport) transport(ctx context.Context) *http.Transport {
+	pool, changed, err := t.ca.load()
+	if err != nil {
+		tflog.Warn(ctx, "Unable to reload the CA, keeping the previous ones", map[string]interface{}{
+			"ca":    t.ca.source,
+			"error": err.Error(),
+		})
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if changed {
+		previous := t.rt
+		t.rt = t.newTransport(pool)
+		previous.CloseIdleConnections()
+	}
+	return t.rt
+}
@@ -0,0 +1,108 @@
+package mimir
+
+import (
+	"context"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeTestCert writes a self-signed certificate and its key to dir.
+func writeTestCert(t *testing.T, dir string, serial int64, notBefore, notAfter time.Time) (string, string) {
+	t.Helper()
+	certPEM, keyPEM := newTestCert(t, serial, notBefore, notAfter)
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	if err := os.WriteFile(certFile, certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, keyPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+	// Make sure the rotation is visible even within the file system timestamp granularity.
+	mtime := time.Now().Add(time.Duration(serial) * time.Second)
+	for _, f := range []string{certFile, keyFile} {
+		if err := os.Chtimes(f, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return certFile, keyFile
+}
+
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	certFile, keyFile := writeTestCert(t, dir, 1, now.Add(-time.Hour), now.Add(23*time.Hour))
+
+	r, err := newCertReloader(certFile, keyFile, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := r.GetClientCertificate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cert.Leaf.SerialNumber.Int64() != 1 {
+		t.Fatalf("got serial %d", cert.Leaf.SerialNumber.Int64())
+	}
+
+	writeTestCert(t, dir, 2, now.Add(-time.Hour), now.Add(23*time.Hour))
+	if cert, _ = r.GetClientCertificate(nil); cert.Leaf.SerialNumber.Int64() != 2 {
+		t.Errorf("expected the rotated certificate, got serial %d", cert.Leaf.SerialNumber.Int64())
+	}
+
+	// A broken rotation keeps the previous certificate.
+	if err := os.WriteFile(keyFile, []byte("garbage"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if cert, err = r.GetClientCertificate(nil); err != nil || cert.Leaf.SerialNumber.Int64() != 2 {
+		t.Errorf("expected the previous certificate, got %v, %v", cert, err)
+	}
+
+	if _, err := newCertReloader(filepath.Join(dir, "missing.crt"), keyFile, false); err == nil {
+		t.Error("expected an error for a missing certificate")
+	}
+}
+
+func TestLogCertExpiry(t *testing.T) {
+	now := time.Now()
+	leaf := &x509.Certificate{NotBefore: now.Add(-22 * time.Hour), NotAfter: now.Add(2 * time.Hour)}
+	if !logCertExpiry(context.Background(), leaf, "tls.crt", now, false) {
+		t.Error("expected a warning 2h before the expiry of a 24h certificate")
+	}
+	leaf = &x509.Certificate{NotBefore: now.Add(-12 * time.Hour), NotAfter: now.Add(12 * time.Hour)}
+	if logCertExpiry(context.Background(), leaf, "tls.crt", now, false) {
+		t.Error("unexpected warning 12h before the expiry of a 24h certificate")
+	}
+}
+
+func TestCATransportReloadsDirectory(t *testing.T) {
+	srv1, ca1 := newTestTLSServer(t, echoHeadersHandler(), 1)
+	srv2, ca2 := newTestTLSServer(t, echoHeadersHandler(), 2)
+
+	dir := t.TempDir()
+	writeCA := func(name string, ca []byte) {
+		if err := os.WriteFile(filepath.Join(dir, name), ca, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeCA("srv1.pem", ca1)
+
+	client, err := NewAPIClient(&apiClientOpt{uri: srv1.URL, ca: dir, headers: map[string]string{}, timeout: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.sendRequest(context.Background(), "", "GET", "/", "", map[string]string{}); err != nil {
+		t.Fatalf("expected srv1 to be trusted: %v", err)
+	}
+	client.uri = srv2.URL
+	if _, err := client.sendRequest(context.Background(), "", "GET", "/", "", map[string]string{}); err == nil {
+		t.Fatal("expected srv2 not to be trusted yet")
+	}
+
+	writeCA("srv2.pem", ca2)
+	if _, err := client.sendRequest(context.Background(), "", "GET", "/", "", map[string]string{}); err != nil {
+		t.Errorf("expected srv2 to be trusted after adding its CA: %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package mimir
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// apiBuildInfoPath is served under the Prometheus HTTP prefix, like the ruler API.
+const apiBuildInfoPath = "/api/v1/status/buildinfo"
+
+type mimirBuildInfo struct {
+	Application string            `json:"application"`
+	Version     string            `json:"version"`
+	Revision    string            `json:"revision"`
+	Branch      string            `json:"branch"`
+	GoVersion   string            `json:"goVersion"`
+	Features    map[string]string `json:"features"`
+}
+
+// mimirFeature is a resource attribute only supported from a given Mimir version. An
+// optional feature is only warned about on older versions, the rest of the resource works.
+type mimirFeature struct {
+	name       string
+	minVersion *version.Version
+	optional   bool
+}
+
+var (
+	featureRuleGroupLabels = mimirFeature{
+		name:       "rule group 'labels'",
+		minVersion: version.Must(version.NewVersion("3.0.0")),
+		optional:   true,
+	}
+	featureQueryOffset = mimirFeature{
+		name:       "'query_offset'",
+		minVersion: version.Must(version.NewVersion("2.13.0")),
+	}
+)
+
+func (client *apiClient) fetchBuildInfo(ctx context.Context) (*mimirBuildInfo, error) {
+	resp, err := client.sendRequest(ctx, "ruler", "GET", apiBuildInfoPath, "", map[string]string{})
+	if err != nil {
+		return nil, err
+	}
+
+	var buildInfo struct {
+		Status string         `json:"status"`
+		Data   mimirBuildInfo `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(resp), &buildInfo); err != nil {
+		return nil, fmt.Errorf("unable to decode build info: %v", err)
+	}
+	return &buildInfo.Data, nil
+}
+
+// detectVersion probes the build info endpoint and stores the cluster version in the client.
+func (client *apiClient) detectVersion(ctx context.Context) error {
+	info, err := client.fetchBuildInfo(ctx)
+	if err != nil {
+		return err
+	}
+	v, err := version.NewVersion(info.Version)
+	if err != nil {
+		// Custom builds (e.g. weekly r-releases) have no semantic version.
+		tflog.Warn(ctx, "Unable to parse the Mimir version, version checks are disabled", map[string]interface{}{
+			"version": info.Version,
+			"error":   err.Error(),
+		})
+		return nil
+	}
+	// Pre-releases of a version already support its features.
+	client.version = v.Core()
+	return nil
+}
+
+// requireFeature reports when the detected Mimir version does not support feature, as a
+// warning for an optional feature and an error otherwise. It reports nothing when the
+// version is unknown (detect_version disabled or probe failed).
+func requireFeature(meta interface{}, feature mimirFeature) diag.Diagnostics {
+	client, ok := meta.(*apiClient)
+	if !ok || client == nil || client.version == nil || !client.version.LessThan(feature.minVersion) {
+		return nil
+	}
+	severity := diag.Error
+	if feature.optional {
+		severity = diag.Warning
+	}
+	return diag.Diagnostics{{
+		Severity: severity,
+		Summary:  fmt.Sprintf("%s requires Mimir >= %s, the target cluster runs %s", feature.name, feature.minVersion, client.version),
+	}}
+}
+
+// ruleGroupFeatureWarnings returns the warnings of the optional features used by ruleGroups
+// the detected Mimir version does not support.
+func ruleGroupFeatureWarnings(meta interface{}, ruleGroups RuleGroups) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, group := range ruleGroups.Groups {
+		if len(group.Labels) == 0 {
+			continue
+		}
+		for _, d := range requireFeature(meta, featureRuleGroupLabels) {
+			d.Summary = fmt.Sprintf("group '%s': %s", group.Name, d.Summary)
+			diags = append(diags, d)
+		}
+	}
+	return diags
+}
@@ -0,0 +1,96 @@
+package mimir
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// buildInfoTestHandler serves the build info of the ruler for mimirVersion.
+func buildInfoTestHandler(mimirVersion string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/prometheus"+apiBuildInfoPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"success","data":{"application":"Grafana Mimir","version":"` + mimirVersion +
+			`","revision":"abc","branch":"HEAD","goVersion":"go1.25.0","features":{"ruler_config_api":"true"}}}`))
+	})
+}
+
+func TestDetectVersion(t *testing.T) {
+	cases := []struct {
+		version      string
+		want         string
+		labelsWarn   bool
+		queryOffsErr bool
+	}{
+		{version: "2.12.1", want: "2.12.1", labelsWarn: true, queryOffsErr: true},
+		{version: "2.17.0", want: "2.17.0", labelsWarn: true},
+		{version: "3.0.0-rc.1", want: "3.0.0"},
+		{version: "r345-7e5c1b2"},
+	}
+	for _, c := range cases {
+		t.Run(c.version, func(t *testing.T) {
+			client := newTestAPIClient(t, buildInfoTestHandler(c.version))
+			if err := client.detectVersion(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			got := ""
+			if client.version != nil {
+				got = client.version.String()
+			}
+			if got != c.want {
+				t.Errorf("detected version %q, want %q", got, c.want)
+			}
+			// Labels are optional, only warned about.
+			if diags := requireFeature(client, featureRuleGroupLabels); diags.HasError() || (len(diags) > 0) != c.labelsWarn {
+				t.Errorf("requireFeature(labels) = %v", diags)
+			}
+			if diags := requireFeature(client, featureQueryOffset); diags.HasError() != c.queryOffsErr {
+				t.Errorf("requireFeature(query_offset) = %v", diags)
+			}
+		})
+	}
+}
+
+func TestRequireFeature_UnknownVersion(t *testing.T) {
+	if diags := requireFeature(nil, featureQueryOffset); len(diags) > 0 {
+		t.Errorf("unexpected diagnostics without client: %v", diags)
+	}
+	if diags := requireFeature(&apiClient{}, featureQueryOffset); len(diags) > 0 {
+		t.Errorf("unexpected diagnostics without detected version: %v", diags)
+	}
+}
+
+func TestRuleGroupFeatureWarnings(t *testing.T) {
+	client := newTestAPIClient(t, buildInfoTestHandler("2.17.0"))
+	if err := client.detectVersion(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	ruleGroups := RuleGroups{Groups: []RuleGroup{
+		{Name: "plain"},
+		{Name: "labelled", Labels: map[string]string{"team": "a"}},
+	}}
+	diags := ruleGroupFeatureWarnings(client, ruleGroups)
+	if len(diags) != 1 || diags.HasError() || !strings.HasPrefix(diags[0].Summary, "group 'labelled': rule group 'labels' requires Mimir >= 3.0.0") {
+		t.Errorf("unexpected diagnostics %v", diags)
+	}
+}
+
+func TestDataSourceBuildInfoRead(t *testing.T) {
+	client := newTestAPIClient(t, buildInfoTestHandler("3.0.6"))
+	d := readTestDataSource(t, dataSourcemimirBuildInfo(), client, map[string]interface{}{})
+	if d.Id() != "3.0.6" || d.Get("application") != "Grafana Mimir" || d.Get("go_version") != "go1.25.0" {
+		t.Errorf("unexpected build info: id=%s application=%v go_version=%v", d.Id(), d.Get("application"), d.Get("go_version"))
+	}
+	if d.Get("features.ruler_config_api") != "true" {
+		t.Errorf("unexpected features %v", d.Get("features"))
+	}
+
+	client.rulerURI += "/wrong"
+	if _, err := client.fetchBuildInfo(context.Background()); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("expected a 404 error, got %v", err)
+	}
+}